@@ -24,10 +24,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var outputFilename string
-var isWWFFcli bool
-var isSOTAcli bool
-var isOverwrite bool
+var adifParams = new(fleprocess.AdifParams)
 
 // adifCmd is executed when choosing the adif option (load and generate adif file)
 var adifCmd = &cobra.Command{
@@ -42,21 +39,15 @@ var adifCmd = &cobra.Command{
 			//TODO: fix this ugly statement (because I am lazy)
 			return fmt.Errorf("Missing input file %s", "")
 		}
-		inputFilename = args[0]
+		adifParams.InputFilename = args[0]
 		if len(args) == 2 {
-			outputFilename = args[1]
+			adifParams.OutputFilename = args[1]
 		}
 		if len(args) > 2 {
 			return fmt.Errorf("Too many arguments.%s", "")
 		}
 
-		err := fleprocess.ProcessAdifCommand(
-			inputFilename,
-			outputFilename,
-			isInterpolateTime,
-			isWWFFcli,
-			isSOTAcli,
-			isOverwrite)
+		err := fleprocess.ProcessAdifCommand(*adifParams)
 		if err != nil {
 			fmt.Println("\nUnable to generate ADIF file:")
 			fmt.Println(err)
@@ -70,8 +61,13 @@ var adifCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(adifCmd)
 
-	adifCmd.PersistentFlags().BoolVarP(&isInterpolateTime, "interpolate", "i", false, "Interpolates the missing time entries.")
-	adifCmd.PersistentFlags().BoolVarP(&isWWFFcli, "wwff", "w", false, "Generates a WWFF ready ADIF file.")
-	adifCmd.PersistentFlags().BoolVarP(&isSOTAcli, "sota", "s", false, "Generates a SOTA ready ADIF file.")
-	adifCmd.PersistentFlags().BoolVarP(&isOverwrite, "overwrite", "o", false, "Overwrites the output file if it exisits")
+	adifCmd.PersistentFlags().BoolVarP(&adifParams.IsInterpolateTime, "interpolate", "i", false, "Interpolates the missing time entries.")
+	adifCmd.PersistentFlags().BoolVarP(&adifParams.IsWWFFcli, "wwff", "w", false, "Generates a WWFF ready ADIF file.")
+	adifCmd.PersistentFlags().BoolVarP(&adifParams.IsSOTAcli, "sota", "s", false, "Generates a SOTA ready ADIF file.")
+	adifCmd.PersistentFlags().BoolVarP(&adifParams.IsPOTAcli, "pota", "p", false, "Generates a POTA ready ADIF file.")
+	adifCmd.PersistentFlags().BoolVarP(&adifParams.IsOverwrite, "overwrite", "o", false, "Overwrites the output file if it exisits")
+	adifCmd.PersistentFlags().BoolVar(&adifParams.IsNferSplit, "nfer-split", false, "Generates one ADIF file per WWFF or POTA reference (multi-reference activation). The whole log is loaded in memory.")
+	adifCmd.PersistentFlags().StringVar(&adifParams.AdifVersion, "adif-version", "3.1.0", "ADIF version to generate (3.1.0, or 3.1.4 and later 3.1 versions). From 3.1.4, WWFF and POTA references use the MY_WWFF_REF/WWFF_REF and MY_POTA_REF/POTA_REF fields.")
+	adifCmd.PersistentFlags().BoolVar(&adifParams.IsSplit, "split", false, "Generates one file per activation (reference and UTC day). The optional output is then a directory. The whole log is loaded in memory.")
+	adifCmd.PersistentFlags().BoolVar(&adifParams.IsAdx, "adx", false, "Generates an ADX (XML ADIF) file instead of an ADI file.")
 }
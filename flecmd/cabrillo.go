@@ -0,0 +1,76 @@
+package flecmd
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"FLEcli/fleprocess"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var outputCabrilloFilename string
+var isOverwriteCabrillo bool
+var cabrilloHeader fleprocess.CabrilloHeader
+
+// cabrilloCmd is executed when choosing the cabrillo option (load FLE file and generate a Cabrillo contest log)
+var cabrilloCmd = &cobra.Command{
+	Use:   "cabrillo [flags] inputFile [outputFile]",
+	Short: "Generates a Cabrillo contest log based on a FLE type shorthand logfile.",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		//if args is empty, throw an error
+		if len(args) == 0 {
+			//TODO: fix this ugly statement (because I am lazy)
+			return fmt.Errorf("Missing input file %s", "")
+		}
+		inputFilename = args[0]
+		if len(args) == 2 {
+			outputCabrilloFilename = args[1]
+		}
+		if len(args) > 2 {
+			return fmt.Errorf("Too many arguments.%s", "")
+		}
+
+		if err := fleprocess.ProcessCabrilloCommand(inputFilename, outputCabrilloFilename, isInterpolateTime, isOverwriteCabrillo, cabrilloHeader); err != nil {
+			fmt.Println("\nUnable to generate Cabrillo file:")
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cabrilloCmd)
+
+	cabrilloCmd.PersistentFlags().BoolVarP(&isInterpolateTime, "interpolate", "i", false, "Interpolates the missing time entries.")
+	cabrilloCmd.PersistentFlags().BoolVarP(&isOverwriteCabrillo, "overwrite", "o", false, "Overwrites the output file if it exisits")
+
+	cabrilloCmd.PersistentFlags().StringVarP(&cabrilloHeader.Contest, "contest", "c", "", "Contest name (e.g. ARRL-RTTY). Mandatory.")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.CategoryOperator, "category-operator", "", "SINGLE-OP, MULTI-OP or CHECKLOG")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.CategoryAssisted, "category-assisted", "", "ASSISTED or NON-ASSISTED")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.CategoryBand, "category-band", "", "Band category (e.g. ALL, 20M, 2M)")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.CategoryMode, "category-mode", "", "CW, DIGI, FM, RTTY, SSB or MIXED")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.CategoryPower, "category-power", "", "HIGH, LOW or QRP")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.CategoryStation, "category-station", "", "Station category (e.g. FIXED, PORTABLE, EXPEDITION)")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.CategoryTransmitter, "category-transmitter", "", "ONE, TWO, LIMITED, UNLIMITED or SWL")
+	cabrilloCmd.PersistentFlags().IntVar(&cabrilloHeader.ClaimedScore, "claimed-score", 0, "Claimed score")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.Operators, "operators", "", "Space separated list of operators (defaults to the operator defined in the log)")
+	cabrilloCmd.PersistentFlags().StringVar(&cabrilloHeader.Location, "location", "", "Location (ARRL section, DX, ...)")
+}
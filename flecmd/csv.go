@@ -24,8 +24,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var outputCsvFilename string
-var isOverwriteCsv bool
+var csvParams = new(fleprocess.CsvParams)
 
 var processCsvCommand = fleprocess.ProcessCsvCommand
 var csvCmd = csvCmdConstructor()
@@ -44,15 +43,15 @@ func csvCmdConstructor() *cobra.Command {
 				//TODO: fix this ugly statement (because I am lazy)
 				return fmt.Errorf("Missing input file %s", "")
 			}
-			inputFilename = args[0]
+			csvParams.InputFilename = args[0]
 			if len(args) == 2 {
-				outputCsvFilename = args[1]
+				csvParams.OutputFilename = args[1]
 			}
 			if len(args) > 2 {
 				return fmt.Errorf("Too many arguments.%s", "")
 			}
 
-			if err := fleprocess.ProcessCsvCommand(inputFilename, outputCsvFilename, isInterpolateTime, isOverwriteCsv); err != nil {
+			if err := fleprocess.ProcessCsvCommand(*csvParams); err != nil {
 				fmt.Println("\nUnable to generate CSV file:")
 				fmt.Println(err)
 				os.Exit(1)
@@ -65,7 +64,10 @@ func csvCmdConstructor() *cobra.Command {
 func init() {
 	rootCmd.AddCommand(csvCmd)
 
-	csvCmd.PersistentFlags().BoolVarP(&isInterpolateTime, "interpolate", "i", false, "Interpolates the missing time entries.")
+	csvCmd.PersistentFlags().BoolVarP(&csvParams.IsInterpolateTime, "interpolate", "i", false, "Interpolates the missing time entries.")
 
-	csvCmd.PersistentFlags().BoolVarP(&isOverwriteCsv, "overwrite", "o", false, "Overwrites the output file if it exisits")
+	csvCmd.PersistentFlags().BoolVarP(&csvParams.IsOverwrite, "overwrite", "o", false, "Overwrites the output file if it exisits")
+	csvCmd.PersistentFlags().BoolVarP(&csvParams.IsChaser, "chaser", "c", false, "Generates a SOTA chaser log with the QSOs made with a summit (ignoring the other QSOs). The whole log is loaded in memory.")
+	csvCmd.PersistentFlags().BoolVar(&csvParams.IsS2S, "s2s", false, "Lists the summit to summit QSOs and generates a S2S CSV file with only these QSOs. The whole log is loaded in memory.")
+	csvCmd.PersistentFlags().BoolVar(&csvParams.IsSplit, "split", false, "Generates one file per activation (summit and UTC day). The optional output is then a directory. The whole log is loaded in memory.")
 }
@@ -0,0 +1,62 @@
+package flecmd
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"FLEcli/fleprocess"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var fmtParams = new(fleprocess.FmtParams)
+
+var fmtCmd = fmtCmdConstructor()
+
+// fmtCmd rewrites FLE files in their canonical form
+func fmtCmdConstructor() *cobra.Command {
+	return &cobra.Command{
+		Use:   "fmt [flags] inputFile...",
+		Short: "Rewrites FLE type shorthand logfiles in a canonical, column-aligned form.",
+		Long: `Rewrites FLE type shorthand logfiles in a canonical, column-aligned form.
+Header keywords, calls and references are normalized, partial times are expanded and the
+date, band and mode are written on their own line. Comments are kept as they are.
+Logs containing errors are not modified.`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			//if args is empty, throw an error
+			if len(args) == 0 {
+				return fmt.Errorf("Missing input file %s", "")
+			}
+			fmtParams.InputFilenames = args
+
+			if err := fleprocess.ProcessFmtCommand(*fmtParams); err != nil {
+				fmt.Println("\nUnable to format:")
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+
+	fmtCmd.PersistentFlags().BoolVar(&fmtParams.IsCheck, "check", false, "Lists the files that are not formatted without modifying them. The exit code is non-zero if any.")
+}
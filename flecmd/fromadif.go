@@ -0,0 +1,64 @@
+package flecmd
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"FLEcli/fleprocess"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var fromAdifParams = new(fleprocess.FromAdifParams)
+
+var fromAdifCmd = fromAdifCmdConstructor()
+
+// fromAdifCmd is executed when choosing the fromadif option (load ADIF file and generate FLE file)
+func fromAdifCmdConstructor() *cobra.Command {
+	return &cobra.Command{
+		Use:   "fromadif [flags] inputFile [outputFile]",
+		Short: "Converts an ADIF file to a FLE type shorthand logfile.",
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			//if args is empty, throw an error
+			if len(args) == 0 {
+				return fmt.Errorf("Missing input file %s", "")
+			}
+			fromAdifParams.InputFilename = args[0]
+			if len(args) == 2 {
+				fromAdifParams.OutputFilename = args[1]
+			}
+			if len(args) > 2 {
+				return fmt.Errorf("Too many arguments.%s", "")
+			}
+
+			if err := fleprocess.ProcessFromAdifCommand(*fromAdifParams); err != nil {
+				fmt.Println("\nUnable to generate FLE file:")
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(fromAdifCmd)
+
+	fromAdifCmd.PersistentFlags().BoolVarP(&fromAdifParams.IsOverwrite, "overwrite", "o", false, "Overwrites the output file if it exisits")
+}
@@ -19,10 +19,14 @@ limitations under the License.
 import (
 	"FLEcli/fleprocess"
 	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 )
 
 var processLoadFile = fleprocess.LoadFile
+var processLoadFileAsJSON = fleprocess.LoadFileAsJSON
+var loadFormat string
 var loadCmd = loadCmdConstructor()
 
 // loadCmd represents the load command
@@ -41,8 +45,24 @@ func loadCmdConstructor() *cobra.Command {
 				return fmt.Errorf("Too many arguments.%s", "")
 			}
 			inputFilename = args[0]
-			//FIXME: we should return the result of the call
-			processLoadFile(inputFilename, isInterpolateTime)
+
+			switch loadFormat {
+			case "", "text":
+				//FIXME: we should return the result of the call
+				processLoadFile(inputFilename, isInterpolateTime)
+			case "json":
+				jsonReport, isReportOK, err := processLoadFileAsJSON(inputFilename, isInterpolateTime)
+				if err != nil {
+					return err
+				}
+				fmt.Print(jsonReport)
+				//A non-zero exit code allows scripts to detect an invalid log
+				if !isReportOK {
+					os.Exit(1)
+				}
+			default:
+				return fmt.Errorf("Unsupported output format \"%s\" (expecting \"text\" or \"json\")", loadFormat)
+			}
 			return nil
 		},
 	}
@@ -52,4 +72,5 @@ func init() {
 	rootCmd.AddCommand(loadCmd)
 
 	loadCmd.PersistentFlags().BoolVarP(&isInterpolateTime, "interpolate", "i", false, "Interpolates the missing time entries.")
+	loadCmd.PersistentFlags().StringVar(&loadFormat, "format", "text", "Output format: \"text\" or \"json\" (QSOs and diagnostics: errors and warnings). In json format, the exit code is non-zero if errors were found.")
 }
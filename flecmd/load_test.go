@@ -19,12 +19,16 @@ limitations under the License.
 import (
 	"FLEcli/fleprocess"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"io/ioutil"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func Test_ExecuteCommand_help(t *testing.T) {
@@ -109,3 +113,122 @@ func mockLoadFile(inputFilename string, isInterpolateTime bool) (filleFullLog []
 	fmt.Print("fileLoad via mock")
 	return nil, true
 }
+
+func Test_ExecuteCommand_jsonFormat(t *testing.T) {
+	processLoadFileAsJSON = mockLoadFileAsJSON
+	loadFormat = "json"
+	defer func() { loadFormat = "text" }()
+
+	//Capture output
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	cmd := loadCmdConstructor()
+
+	cmd.SetArgs([]string{"data.txt"})
+	cmdErr := cmd.Execute()
+
+	//Close the capture and get the data
+	w.Close()
+	out, _ := ioutil.ReadAll(r)
+	os.Stdout = rescueStdout
+
+	if cmdErr != nil {
+		t.Fatalf("Unexpected error executing command: %s", cmdErr)
+	}
+
+	if string(out) != "{\"inputFile\":\"data.txt\"}\n" {
+		t.Fatalf("Expected the JSON report. Got \"%s\"", string(out))
+	}
+}
+
+//The whole standard output must be a valid JSON document, even when a config file is used
+func Test_ExecuteCommand_jsonFormatWithConfigFile(t *testing.T) {
+	processLoadFileAsJSON = fleprocess.LoadFileAsJSON
+	defer func() { loadFormat = "text"; cfgFile = "" }()
+	//The config file changes the default region of the band plan: it must not leak into the other tests
+	savedRegion := fleprocess.DefaultRegion()
+	t.Cleanup(func() {
+		fleprocess.SetDefaultRegion(savedRegion)
+		viper.Reset()
+	})
+
+	directory, err := ioutil.TempDir("", "FLEcli-json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	configFilename := filepath.Join(directory, "FLEcli.yaml")
+	ioutil.WriteFile(configFilename, []byte("iaru_region: 2\n"), 0644)
+	inputFilename := filepath.Join(directory, "log.txt")
+	ioutil.WriteFile(inputFilename, []byte("mycall on4kjm\ndate 2020-05-23 40m cw\n1310 ik5zve\n"), 0644)
+
+	//Capture output
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	rootCmd.SetArgs([]string{"--config", configFilename, "load", "--format", "json", inputFilename})
+	cmdErr := rootCmd.Execute()
+
+	//Close the capture and get the data
+	w.Close()
+	out, _ := ioutil.ReadAll(r)
+	os.Stdout = rescueStdout
+
+	if cmdErr != nil {
+		t.Fatalf("Unexpected error executing command: %s", cmdErr)
+	}
+	var report fleprocess.LoadReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("The output is not a valid JSON document: %s\n%s", err, out)
+	}
+	if report.QsoCount != 1 || len(report.Diagnostics) != 0 {
+		t.Errorf("Unexpected report: %s", out)
+	}
+}
+
+func Test_ExecuteCommand_unsupportedFormat(t *testing.T) {
+	loadFormat = "xml"
+	defer func() { loadFormat = "text" }()
+
+	cmd := loadCmdConstructor()
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"data.txt"})
+	cmd.Execute()
+	out, err := ioutil.ReadAll(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOutputStart := "Error: Unsupported output format \"xml\" (expecting \"text\" or \"json\")\nUsage"
+	if !strings.HasPrefix(string(out), expectedOutputStart) {
+		t.Fatalf("expected to start with \"%s\" got \"%s\"", expectedOutputStart, string(out))
+	}
+}
+
+func mockLoadFileAsJSON(inputFilename string, isInterpolateTime bool) (jsonReport string, isProcessedOK bool, err error) {
+	return fmt.Sprintf("{\"inputFile\":\"%s\"}\n", inputFilename), true, nil
+}
+
+//In text format, the command doesn't exit when the log has errors
+func Test_ExecuteCommand_textFormatWithErrors(t *testing.T) {
+	processLoadFile = fleprocess.LoadFile
+	defer func() { processLoadFile = mockLoadFile }()
+
+	directory, err := ioutil.TempDir("", "FLEcli-text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	inputFilename := filepath.Join(directory, "log.txt")
+	ioutil.WriteFile(inputFilename, []byte("mycall on4kjm\ndate 2020-05-23 40m cw\n1310 ik5zve foo\n"), 0644)
+
+	cmd := loadCmdConstructor()
+	cmd.SetArgs([]string{inputFilename})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error executing command: %s", err)
+	}
+}
@@ -23,6 +23,7 @@ THE SOFTWARE.
 */
 
 import (
+	"FLEcli/fleprocess"
 	"fmt"
 	"os"
 
@@ -79,7 +80,16 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
+	// The message goes to stderr so that it doesn't corrupt the machine readable outputs (load --format json)
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	}
+
+	// The IARU region of the band plan can be defined in the config file ("iaru_region: 1")
+	if region := viper.GetString("iaru_region"); region != "" {
+		if err := fleprocess.SetDefaultRegion(region); err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid config file:", err)
+			os.Exit(1)
+		}
 	}
 }
@@ -0,0 +1,56 @@
+package flecmd
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"FLEcli/fleprocess"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var validateAdifCmd = validateAdifCmdConstructor()
+
+// validateAdifCmd is executed when choosing the validate-adif option (load and check an ADIF file)
+func validateAdifCmdConstructor() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate-adif inputFile",
+		Short: "Checks the syntax, the field types and the enumerated values of an ADIF file.",
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			//if args is empty, throw an error
+			if len(args) == 0 {
+				return fmt.Errorf("Missing input file %s", "")
+			}
+			if len(args) > 1 {
+				return fmt.Errorf("Too many arguments.%s", "")
+			}
+
+			if err := fleprocess.ProcessValidateAdifCommand(args[0]); err != nil {
+				fmt.Println("\nADIF validation failed:")
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(validateAdifCmd)
+}
@@ -18,114 +18,316 @@ limitations under the License.
 
 import (
 	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
-//ProcessAdifCommand loads an FLE input to produce an adif file (eventually in WWFF format). It is called from the COBRA interface
-func ProcessAdifCommand(inputFilename, outputFilename string, isInterpolateTime, isWWFFcli, isSOTAcli, isOverwrite bool) error {
+//AdifParams is holding all the parameters required to generate an ADIF file
+type AdifParams struct {
+	InputFilename     string
+	OutputFilename    string
+	IsInterpolateTime bool
+	IsWWFFcli         bool
+	IsSOTAcli         bool
+	IsPOTAcli         bool
+	IsOverwrite       bool
+	IsNferSplit       bool
+	IsSplit           bool
+	AdifVersion       string
+	IsAdx             bool
+}
+
+//defaultAdifVersion is the ADIF version generated if none is specified
+const defaultAdifVersion = "3.1.0"
+
+//refFieldsAdifVersion is the first ADIF version where the WWFF and POTA references have dedicated fields.
+//The ADIF versions that can be generated are 3.1.0 and the 3.1 versions starting with this one.
+var refFieldsAdifVersion = [3]int{3, 1, 4}
+
+//adifVersion returns the requested ADIF version or the default one
+func adifVersion(adifParams AdifParams) string {
+	if adifParams.AdifVersion == "" {
+		return defaultAdifVersion
+	}
+	return adifParams.AdifVersion
+}
+
+//parseAdifVersion returns the numeric components of an ADIF version (e.g. "3.1.4")
+func parseAdifVersion(version string) (components [3]int, isValid bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) != len(components) {
+		return components, false
+	}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return components, false
+		}
+		components[i] = number
+	}
+	return components, true
+}
+
+//compareAdifVersions returns -1, 0 or 1 if the version a is lower, equal or greater than the version b
+func compareAdifVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+//isSupportedAdifVersion returns true if the ADIF version is 3.1.0 or a 3.1 version from 3.1.4 on
+func isSupportedAdifVersion(version string) bool {
+	components, isValid := parseAdifVersion(version)
+	if !isValid || components[0] != refFieldsAdifVersion[0] || components[1] != refFieldsAdifVersion[1] {
+		return false
+	}
+	return components[2] == 0 || compareAdifVersions(components, refFieldsAdifVersion) >= 0
+}
+
+//isRefFieldsAdifVersion returns true if the generated ADIF version has the dedicated WWFF and POTA reference fields
+func isRefFieldsAdifVersion(adifParams AdifParams) bool {
+	components, isValid := parseAdifVersion(adifVersion(adifParams))
+	return isValid && compareAdifVersions(components, refFieldsAdifVersion) >= 0
+}
 
-	//Validate of build the output filenaem
+//adifExtension returns the extension of the generated files (.adx for the XML format)
+func adifExtension(adifParams AdifParams) string {
+	if adifParams.IsAdx {
+		return ".adx"
+	}
+	return ".adi"
+}
+
+//ProcessAdifCommand loads an FLE input to produce an adif file (eventually in WWFF, SOTA or POTA format). It is called from the COBRA interface
+func ProcessAdifCommand(adifParams AdifParams) error {
+
+	if adifParams.IsNferSplit && !(adifParams.IsWWFFcli || adifParams.IsPOTAcli) {
+		return fmt.Errorf("Generating one file per reference requires a WWFF or POTA ADIF file")
+	}
+
+	if !isSupportedAdifVersion(adifVersion(adifParams)) {
+		return fmt.Errorf("Unsupported ADIF version [%s] (expecting 3.1.0, or 3.1.4 and later)", adifParams.AdifVersion)
+	}
+	if adifParams.IsWWFFcli && adifParams.IsPOTAcli && !isRefFieldsAdifVersion(adifParams) {
+		return fmt.Errorf("WWFF and POTA references can't be combined in an ADIF %s file (both use the SIG fields). Use ADIF version 3.1.4", defaultAdifVersion)
+	}
+
+	//Validate of build the output filenaem (or directory when splitting the output)
 	var verifiedOutputFilename string
 	var err error
 
-	if verifiedOutputFilename, err = buildOutputFilename(outputFilename, inputFilename, isOverwrite, ".adi"); err != nil {
-		return err
+	if adifParams.IsSplit {
+		if verifiedOutputFilename, err = buildOutputDirectory(adifParams.OutputFilename, adifParams.InputFilename); err != nil {
+			return err
+		}
+	} else if adifParams.IsNferSplit {
+		//The output filename is only the base of the names of the files per reference, which are checked before writing
+		if verifiedOutputFilename, err = defaultOutputFilename(adifParams.OutputFilename, adifParams.InputFilename, adifExtension(adifParams)); err != nil {
+			return err
+		}
+	} else {
+		if verifiedOutputFilename, err = buildOutputFilename(adifParams.OutputFilename, adifParams.InputFilename, adifParams.IsOverwrite, adifExtension(adifParams)); err != nil {
+			return err
+		}
+	}
+
+	//Without splitting, the log is streamed to the output file
+	if !(adifParams.IsNferSplit || adifParams.IsSplit) {
+		if adifParams.IsAdx {
+			return streamLogToFile(adifParams.InputFilename, adifParams.IsInterpolateTime, verifiedOutputFilename, newAdifChecker(adifParams),
+				func(writer io.Writer) LogWriter { return NewAdxWriter(writer, adifParams) }, "ADX")
+		}
+		return streamLogToFile(adifParams.InputFilename, adifParams.IsInterpolateTime, verifiedOutputFilename, newAdifChecker(adifParams),
+			func(writer io.Writer) LogWriter { return NewAdifWriter(writer, adifParams) }, "ADIF")
 	}
 
 	//Load the input file
 	var loadedLogFile []LogLine
 	var isLoadedOK bool
 
-	if loadedLogFile, isLoadedOK = LoadFile(inputFilename, isInterpolateTime); isLoadedOK == false {
+	if loadedLogFile, isLoadedOK = LoadFile(adifParams.InputFilename, adifParams.IsInterpolateTime); isLoadedOK == false {
 		return fmt.Errorf("There were input file parsing errors. Could not generate ADIF file")
 	}
 
 	//Check if we have all the necessary data
-	if err := validateDataforAdif(loadedLogFile, isWWFFcli, isSOTAcli); err != nil {
+	if err := validateDataforAdif(loadedLogFile, adifParams); err != nil {
 		return err
 	}
 
-	//Write the output file with the checked data
-	OutputAdif(verifiedOutputFilename, loadedLogFile, isWWFFcli, isSOTAcli)
+	outputLogs := []outputLog{{outputFilename: verifiedOutputFilename, log: loadedLogFile}}
+
+	//Multi-reference activation: one file is generated per reference
+	if adifParams.IsNferSplit {
+		outputLogs = buildNferLogs(loadedLogFile, adifParams, verifiedOutputFilename)
+	}
+
+	//One file is generated per activation (reference and UTC day)
+	if adifParams.IsSplit {
+		var activationLogs []outputLog
+		for _, splitLog := range outputLogs {
+			activationLogs = append(activationLogs, splitLogPerActivation(splitLog.log, verifiedOutputFilename, adifExtension(adifParams), adifRefSelectors(adifParams))...)
+		}
+		outputLogs = activationLogs
+	}
+
+	//Check all the file names before writing anything
+	if err := verifyOutputFilenames(outputLogs, adifParams.InputFilename, adifParams.IsOverwrite, adifExtension(adifParams)); err != nil {
+		return err
+	}
+	for _, splitLog := range outputLogs {
+		if isWwffRefDropped(splitLog.log, adifParams) {
+			fmt.Printf("Warning: several MY-WWFF references can't be written in an ADIF %s file: MY_WWFF_REF is left out of \"%s\"\n", adifVersion(adifParams), splitLog.outputFilename)
+		}
+		if adifParams.IsAdx {
+			OutputAdx(splitLog.outputFilename, splitLog.log, adifParams)
+		} else {
+			OutputAdif(splitLog.outputFilename, splitLog.log, adifParams)
+		}
+	}
 
-	//If we reached this point, everything was processed OK and the file generated
+	//If we reached this point, everything was processed OK and the files generated
 	return nil
 }
 
 //validateDataforAdif checks whether all the required data is present
 //The details of the mandatory files can be found at http://wwff.co/rules-faq/confirming-and-sending-log/
-func validateDataforAdif(loadedLogFile []LogLine, isWWFFcli, isSOTAcli bool) error {
+//and https://docs.pota.app/docs/activator_reference/submitting_logs.html
+func validateDataforAdif(loadedLogFile []LogLine, adifParams AdifParams) error {
+	checker := newAdifChecker(adifParams)
+	for _, logLine := range loadedLogFile {
+		checker.check(logLine)
+	}
+	return checker.err()
+}
 
-	//do we have QSOs at all?
-	if len(loadedLogFile) == 0 {
-		return fmt.Errorf("No QSO found")
+//newAdifChecker creates a checker for the data required by the ADIF file
+func newAdifChecker(adifParams AdifParams) *logChecker {
+	return &logChecker{
+		checkSegmentHeader: func(logLine LogLine, location string) error {
+			return validateSegmentHeaderForAdif(logLine, adifParams, location)
+		},
 	}
+}
 
-	//MySOTA, MyWWFF and MyCall are header values. If missing on the first line, it will be missing at every line
-	if loadedLogFile[0].MyCall == "" {
-		return fmt.Errorf("Missing MyCall")
+//validateSegmentHeaderForAdif checks the header values required for the ADIF file (as found on the first line of a segment)
+func validateSegmentHeaderForAdif(logLine LogLine, adifParams AdifParams, location string) error {
+	if logLine.MyCall == "" {
+		return fmt.Errorf("Missing MyCall%s", location)
 	}
-	if isSOTAcli {
-		if loadedLogFile[0].MySOTA == "" {
-			return fmt.Errorf("Missing MY-SOTA reference")
-		}
+	if field, isInvalid := firstInvalidField(logLine, headerFields); isInvalid {
+		return fmt.Errorf("Invalid %s value%s", field, location)
 	}
-	if isWWFFcli {
-		if loadedLogFile[0].MyWWFF == "" {
-			return fmt.Errorf("Missing MY-WWFF reference")
-		}
-		if loadedLogFile[0].Operator == "" {
-			return fmt.Errorf("Missing Operator call sign")
+	if adifParams.IsSOTAcli {
+		if logLine.MySOTA == "" {
+			return fmt.Errorf("Missing MY-SOTA reference%s", location)
 		}
 	}
-
-	var errorsBuffer strings.Builder
-	//We accumulate the errors messages
-	for i := 0; i < len(loadedLogFile); i++ {
-
-		//Compute the error location for a meaning full error
-		var errorLocation string
-		if loadedLogFile[i].Time == "" {
-			errorLocation = fmt.Sprintf("for log entry #%d", i+1)
-		} else {
-			errorLocation = fmt.Sprintf("for log entry at %s (#%d)", loadedLogFile[i].Time, i+1)
+	if adifParams.IsPOTAcli {
+		if logLine.MyPOTA == "" {
+			return fmt.Errorf("Missing MY-POTA reference%s", location)
 		}
-
-		if loadedLogFile[i].Date == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
+		for _, ref := range splitRefList(logLine.MyPOTA) {
+			if _, diagnostic := ValidatePota(ref); diagnostic != nil {
+				return fmt.Errorf("Invalid MY-POTA reference%s: %s", location, diagnostic.Message)
 			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing date %s", errorLocation))
 		}
-		if loadedLogFile[i].Band == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
-			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing band %s", errorLocation))
+	}
+	if adifParams.IsWWFFcli {
+		if logLine.MyWWFF == "" {
+			return fmt.Errorf("Missing MY-WWFF reference%s", location)
 		}
-		if loadedLogFile[i].Mode == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
-			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing mode %s", errorLocation))
+		if logLine.Operator == "" {
+			return fmt.Errorf("Missing Operator call sign%s", location)
 		}
-		if loadedLogFile[i].Call == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
+		refs := splitRefList(logLine.MyWWFF)
+		for _, ref := range refs {
+			if _, diagnostic := ValidateWwff(ref); diagnostic != nil {
+				return fmt.Errorf("Invalid MY-WWFF reference%s: %s", location, diagnostic.Message)
 			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing call %s", errorLocation))
 		}
-		if loadedLogFile[i].Time == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
-			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing QSO time %s", errorLocation))
+		//Unlike MY_POTA_REF, the MY_WWFF_REF field holds a single reference
+		if len(refs) > 1 && isRefFieldsAdifVersion(adifParams) && !adifParams.IsNferSplit {
+			return fmt.Errorf("Several MY-WWFF references%s can't be written in an ADIF %s file. Use --nfer-split to generate one file per reference", location, adifVersion(adifParams))
+		}
+	}
+	return nil
+}
+
+//adifRefSelectors returns the references used to split the ADIF output per activation.
+//If no specific ADIF type is requested, all the activation references are used.
+func adifRefSelectors(adifParams AdifParams) (selectors []refSelector) {
+	if adifParams.IsWWFFcli {
+		selectors = append(selectors, selectMyWWFF)
+	}
+	if adifParams.IsSOTAcli {
+		selectors = append(selectors, selectMySOTA)
+	}
+	if adifParams.IsPOTAcli {
+		selectors = append(selectors, selectMyPOTA)
+	}
+	if len(selectors) == 0 {
+		selectors = []refSelector{selectMyWWFF, selectMySOTA, selectMyPOTA}
+	}
+	return selectors
+}
+
+//isWwffRefDropped returns true if MY_WWFF_REF can't be written for some QSOs of the log (several references in the same field).
+//It happens for the POTA files of a WWFF and POTA n-fer split, the WWFF references having their own files.
+func isWwffRefDropped(log []LogLine, adifParams AdifParams) bool {
+	if !adifParams.IsWWFFcli || !isRefFieldsAdifVersion(adifParams) {
+		return false
+	}
+	for _, logLine := range log {
+		if len(splitRefList(logLine.MyWWFF)) > 1 {
+			return true
 		}
 	}
-	if errorsBuffer.String() != "" {
-		return fmt.Errorf(errorsBuffer.String())
+	return false
+}
+
+//buildNferLogs duplicates the log for each of the activated WWFF or POTA references.
+//When the log contains several segments, each file only contains the QSOs made from that reference.
+//The output file name is derived from the supplied one by appending the reference.
+func buildNferLogs(fullLog []LogLine, adifParams AdifParams, outputFilename string) (nferLogs []outputLog) {
+	extension := filepath.Ext(outputFilename)
+	outputRootPart := outputFilename[0 : len(outputFilename)-len(extension)]
+
+	if adifParams.IsWWFFcli {
+		nferLogs = append(nferLogs, splitLogPerRef(fullLog, outputRootPart, extension,
+			func(logLine *LogLine) *string { return &logLine.MyWWFF })...)
 	}
+	if adifParams.IsPOTAcli {
+		nferLogs = append(nferLogs, splitLogPerRef(fullLog, outputRootPart, extension,
+			func(logLine *LogLine) *string { return &logLine.MyPOTA })...)
+	}
+	return nferLogs
+}
 
-	//If we reached here, all is ok
-	return nil
+//splitLogPerRef creates a log per reference found in the reference list field selected by refField
+func splitLogPerRef(fullLog []LogLine, outputRootPart, extension string, refField func(logLine *LogLine) *string) (nferLogs []outputLog) {
+	//position of the reference in nferLogs
+	refIndex := make(map[string]int)
+
+	for _, logLine := range fullLog {
+		for _, ref := range splitRefList(*refField(&logLine)) {
+			index, ok := refIndex[ref]
+			if !ok {
+				index = len(nferLogs)
+				refIndex[ref] = index
+				nferLogs = append(nferLogs, outputLog{reference: ref, outputFilename: outputRootPart + "_" + ref + extension})
+			}
+			nferLine := logLine
+			*refField(&nferLine) = ref
+			nferLogs[index].log = append(nferLogs[index].log, nferLine)
+		}
+	}
+	return nferLogs
 }
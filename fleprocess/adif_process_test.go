@@ -18,14 +18,18 @@ limitations under the License.
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 func Test_validateDataforAdif(t *testing.T) {
 	type args struct {
 		loadedLogFile []LogLine
-		isWWFFcli     bool
-		isSOTAcli     bool
+		adifParams    AdifParams
 	}
 	tests := []struct {
 		name string
@@ -34,7 +38,7 @@ func Test_validateDataforAdif(t *testing.T) {
 	}{
 		{
 			"Happy Case (no sota or wwff)",
-			args{isWWFFcli: false, isSOTAcli: false, loadedLogFile: []LogLine{
+			args{adifParams: AdifParams{}, loadedLogFile: []LogLine{
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "time", Call: "call"},
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "time", Call: "call"},
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "time", Call: "call"}},
@@ -43,21 +47,37 @@ func Test_validateDataforAdif(t *testing.T) {
 		},
 		{
 			"No data",
-			args{isWWFFcli: false, isSOTAcli: false, loadedLogFile: []LogLine{}},
+			args{adifParams: AdifParams{}, loadedLogFile: []LogLine{}},
 			fmt.Errorf("No QSO found"),
 		},
 		{
 			"Missing Date",
-			args{isWWFFcli: false, isSOTAcli: false, loadedLogFile: []LogLine{
+			args{adifParams: AdifParams{}, loadedLogFile: []LogLine{
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "12:01", Call: "call"},
 				{Date: "", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "12:02", Call: "call"},
 				{Date: "", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "12:03", Call: "call"}},
 			},
 			fmt.Errorf("missing date for log entry at 12:02 (#2), missing date for log entry at 12:03 (#3)"),
 		},
+		{
+			"Invalid values",
+			args{adifParams: AdifParams{}, loadedLogFile: []LogLine{
+				{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:01", Call: "call"},
+				{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:02", Call: "call", RSTrcvd: "599", InvalidFields: FieldSet(0).with(FieldRSTrcvd, true)},
+				{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:03", Call: "call", InvalidFields: FieldSet(0).with(FieldDate, true).with(FieldCall, true)}},
+			},
+			fmt.Errorf("invalid rstRcvd value for log entry at 12:02 (#2), invalid date value for log entry at 12:03 (#3), invalid call value for log entry at 12:03 (#3)"),
+		},
+		{
+			"Invalid MyCall",
+			args{adifParams: AdifParams{}, loadedLogFile: []LogLine{
+				{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:01", Call: "call", InvalidFields: FieldSet(0).with(FieldMyCall, true)}},
+			},
+			fmt.Errorf("Invalid myCall value"),
+		},
 		{
 			"Missing MyCall",
-			args{isWWFFcli: true, isSOTAcli: true, loadedLogFile: []LogLine{
+			args{adifParams: AdifParams{IsWWFFcli: true, IsSOTAcli: true}, loadedLogFile: []LogLine{
 				{Date: "date", MyCall: "", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "12:01", Call: "call"},
 				{Date: "date", MyCall: "", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "12:02", Call: "call"},
 				{Date: "date", MyCall: "", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "12:03", Call: "call"}},
@@ -66,7 +86,7 @@ func Test_validateDataforAdif(t *testing.T) {
 		},
 		{
 			"Missing MySota",
-			args{isWWFFcli: false, isSOTAcli: true, loadedLogFile: []LogLine{
+			args{adifParams: AdifParams{IsSOTAcli: true}, loadedLogFile: []LogLine{
 				{Date: "date", MyCall: "myCall", MySOTA: "", Mode: "mode", Band: "band", Time: "time", Call: "call"},
 				{Date: "date", MyCall: "myCall", MySOTA: "", Mode: "mode", Band: "band", Time: "time", Call: "call"},
 				{Date: "date", MyCall: "myCall", MySOTA: "", Mode: "mode", Band: "band", Time: "time", Call: "call"}},
@@ -75,7 +95,7 @@ func Test_validateDataforAdif(t *testing.T) {
 		},
 		{
 			"Misc. missing data (Band, Time, Mode, Call)",
-			args{isWWFFcli: false, isSOTAcli: false, loadedLogFile: []LogLine{
+			args{adifParams: AdifParams{}, loadedLogFile: []LogLine{
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "", Time: "", Call: "call"},
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "", Band: "band", Time: "12:02", Call: "call"},
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "12:03", Call: ""}},
@@ -84,7 +104,7 @@ func Test_validateDataforAdif(t *testing.T) {
 		},
 		{
 			"Missing MY-WWFF",
-			args{isWWFFcli: true, isSOTAcli: false, loadedLogFile: []LogLine{
+			args{adifParams: AdifParams{IsWWFFcli: true}, loadedLogFile: []LogLine{
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", MyWWFF: "", Mode: "mode", Band: "band", Time: "time", Call: "call"},
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", MyWWFF: "", Mode: "mode", Band: "band", Time: "time", Call: "call"},
 				{Date: "date", MyCall: "myCall", MySOTA: "mySota", MyWWFF: "", Mode: "mode", Band: "band", Time: "time", Call: "call"}},
@@ -93,17 +113,49 @@ func Test_validateDataforAdif(t *testing.T) {
 		},
 		{
 			"Missing MY-WWFF",
-			args{isWWFFcli: true, isSOTAcli: false, loadedLogFile: []LogLine{
+			args{adifParams: AdifParams{IsWWFFcli: true}, loadedLogFile: []LogLine{
 				{Date: "date", MyCall: "myCall", MyWWFF: "myWwff", Operator: "", Mode: "mode", Band: "band", Time: "time", Call: "call"},
 				{Date: "date", MyCall: "myCall", MyWWFF: "myWwff", Operator: "", Mode: "mode", Band: "band", Time: "time", Call: "call"},
 				{Date: "date", MyCall: "myCall", MyWWFF: "myWwff", Operator: "", Mode: "mode", Band: "band", Time: "time", Call: "call"}},
 			},
 			fmt.Errorf("Missing Operator call sign"),
 		},
+		{
+			"Missing MY-POTA",
+			args{adifParams: AdifParams{IsPOTAcli: true}, loadedLogFile: []LogLine{
+				{Date: "date", MyCall: "myCall", MyWWFF: "myWwff", MyPOTA: "", Mode: "mode", Band: "band", Time: "time", Call: "call"},
+				{Date: "date", MyCall: "myCall", MyWWFF: "myWwff", MyPOTA: "", Mode: "mode", Band: "band", Time: "time", Call: "call"}},
+			},
+			fmt.Errorf("Missing MY-POTA reference"),
+		},
+		{
+			"Invalid MY-WWFF in the n-fer list",
+			args{adifParams: AdifParams{IsWWFFcli: true}, loadedLogFile: []LogLine{
+				{Date: "date", MyCall: "myCall", MyWWFF: "ONFF-0258,*ONFF-25", Operator: "myCall", Mode: "mode", Band: "band", Time: "time", Call: "call"}},
+			},
+			fmt.Errorf("Invalid MY-WWFF reference: [*ONFF-25] is an invalid WWFF reference"),
+		},
+		{
+			"Missing MY-SOTA in the second segment",
+			args{adifParams: AdifParams{IsSOTAcli: true}, loadedLogFile: []LogLine{
+				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "time", Call: "call"},
+				{Date: "date", MyCall: "myCall", MySOTA: "mySota", Mode: "mode", Band: "band", Time: "time", Call: "call"},
+				{Date: "date", MyCall: "myCall2", MySOTA: "", Mode: "mode", Band: "band", Time: "time", Call: "call"}},
+			},
+			fmt.Errorf("Missing MY-SOTA reference (segment #2 starting at log entry #3)"),
+		},
+		{
+			"Happy case POTA",
+			args{adifParams: AdifParams{IsPOTAcli: true}, loadedLogFile: []LogLine{
+				{Date: "date", MyCall: "myCall", MyPOTA: "ON-00259", Mode: "mode", Band: "band", Time: "time", Call: "call"},
+				{Date: "date", MyCall: "myCall", MyPOTA: "ON-00259", Mode: "mode", Band: "band", Time: "time", Call: "call"}},
+			},
+			nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := validateDataforAdif(tt.args.loadedLogFile, tt.args.isWWFFcli, tt.args.isSOTAcli)
+			got := validateDataforAdif(tt.args.loadedLogFile, tt.args.adifParams)
 
 			//Test the error message, if any
 			if got != nil && tt.want != nil {
@@ -121,12 +173,7 @@ func Test_validateDataforAdif(t *testing.T) {
 
 func TestProcessAdifCommand(t *testing.T) {
 	type args struct {
-		inputFilename     string
-		outputFilename    string
-		isInterpolateTime bool
-		isWWFFcli         bool
-		isSOTAcli         bool
-		isOverwrite       bool
+		adifParams AdifParams
 	}
 	tests := []struct {
 		name    string
@@ -135,30 +182,235 @@ func TestProcessAdifCommand(t *testing.T) {
 	}{
 		{
 			"Bad output filename (directory)",
-			args{inputFilename: "../test/data/fle-4-no-qso.txt", outputFilename: "../test/data", isInterpolateTime: false, isOverwrite: false},
+			args{adifParams: AdifParams{InputFilename: "../test/data/fle-4-no-qso.txt", OutputFilename: "../test/data", IsInterpolateTime: false, IsOverwrite: false}},
 			true,
 		},
 		{
 			"input file parsing errors (missing band)",
-			args{inputFilename: "../test/data/fle-3-error.txt", outputFilename: "", isInterpolateTime: false, isOverwrite: false},
+			args{adifParams: AdifParams{InputFilename: "../test/data/fle-3-error.txt", OutputFilename: "", IsInterpolateTime: false, IsOverwrite: false}},
 			true,
 		},
 		{
 			"input file parsing errors (wrong call)",
-			args{inputFilename: "../test/data/fle-5-wrong-call.txt", outputFilename: "", isInterpolateTime: false, isOverwrite: false},
+			args{adifParams: AdifParams{InputFilename: "../test/data/fle-5-wrong-call.txt", OutputFilename: "", IsInterpolateTime: false, IsOverwrite: false}},
 			true,
 		},
 		{
 			"No QSO in loaded file",
-			args{inputFilename: "../test/data/fle-4-no-qso.txt", outputFilename: "", isInterpolateTime: false, isOverwrite: false},
+			args{adifParams: AdifParams{InputFilename: "../test/data/fle-4-no-qso.txt", OutputFilename: "", IsInterpolateTime: false, IsOverwrite: false}},
+			true,
+		},
+		{
+			"Unsupported ADIF version",
+			args{adifParams: AdifParams{InputFilename: "../test/data/fle-1.txt", OutputFilename: "", AdifVersion: "2.2"}},
+			true,
+		},
+		{
+			"WWFF and POTA in ADIF 3.1.0",
+			args{adifParams: AdifParams{InputFilename: "../test/data/fle-1.txt", OutputFilename: "", IsWWFFcli: true, IsPOTAcli: true, AdifVersion: "3.1.0"}},
 			true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ProcessAdifCommand(tt.args.inputFilename, tt.args.outputFilename, tt.args.isInterpolateTime, tt.args.isWWFFcli, tt.args.isSOTAcli, tt.args.isOverwrite); (err != nil) != tt.wantErr {
+			if err := ProcessAdifCommand(tt.args.adifParams); (err != nil) != tt.wantErr {
 				t.Errorf("ProcessCsvCommand() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+//The ADX output is generated with the .adx extension, whether the log is streamed or split
+func Test_isSupportedAdifVersion(t *testing.T) {
+	tests := []struct {
+		version         string
+		wantIsSupported bool
+		wantIsRefFields bool
+	}{
+		{"3.1.0", true, false},
+		{"3.1.3", false, false},
+		{"3.1.4", true, true},
+		{"3.1.5", true, true},
+		{"3.1.10", true, true},
+		{"3.2.0", false, true},
+		{"2.2", false, false},
+		{"3.1.x", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := isSupportedAdifVersion(tt.version); got != tt.wantIsSupported {
+				t.Errorf("isSupportedAdifVersion() = %v, want %v", got, tt.wantIsSupported)
+			}
+			if got := isRefFieldsAdifVersion(AdifParams{AdifVersion: tt.version}); got != tt.wantIsRefFields {
+				t.Errorf("isRefFieldsAdifVersion() = %v, want %v", got, tt.wantIsRefFields)
+			}
+		})
+	}
+}
+
+func TestProcessAdifCommand_adx(t *testing.T) {
+	directory, err := ioutil.TempDir("", "FLEcli-adx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	input, _ := ioutil.ReadFile("../test/data/sample_wwff_sota.txt")
+	inputFilename := filepath.Join(directory, "activation.txt")
+	ioutil.WriteFile(inputFilename, input, 0644)
+	splitDirectory := filepath.Join(directory, "split")
+	os.Mkdir(splitDirectory, 0755)
+
+	tests := []struct {
+		name       string
+		adifParams AdifParams
+		outputGlob string
+	}{
+		{"Streamed", AdifParams{InputFilename: inputFilename, IsSOTAcli: true, IsAdx: true}, filepath.Join(directory, "activation.adx")},
+		{"Split", AdifParams{InputFilename: inputFilename, OutputFilename: splitDirectory, IsSOTAcli: true, IsSplit: true, IsAdx: true}, filepath.Join(splitDirectory, "*.adx")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ProcessAdifCommand(tt.adifParams); err != nil {
+				t.Fatalf("ProcessAdifCommand() unexpected error: %v", err)
+			}
+			outputFilenames, _ := filepath.Glob(tt.outputGlob)
+			if len(outputFilenames) == 0 {
+				t.Fatalf("ProcessAdifCommand() didn't generate %s", tt.outputGlob)
+			}
+			for _, outputFilename := range outputFilenames {
+				if output, _ := ioutil.ReadFile(outputFilename); !strings.HasPrefix(string(output), "<?xml") || !strings.HasSuffix(string(output), "</ADX>\n") {
+					t.Errorf("%s is not an ADX file: %s", outputFilename, output)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessAdifCommand_nferSplit(t *testing.T) {
+	directory, err := ioutil.TempDir("", "FLEcli-nfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	inputFilename := filepath.Join(directory, "s.txt")
+	ioutil.WriteFile(inputFilename, []byte("mycall on4kjm/p\noperator on4kjm\nmywwff onff-0259, onff-0001\ndate 2020-05-24 40m cw\n1310 ik5zve\n"), 0644)
+	//The base output file is never written: it must not prevent the generation
+	ioutil.WriteFile(filepath.Join(directory, "s.adi"), []byte("previous file"), 0644)
+	adifParams := AdifParams{InputFilename: inputFilename, IsWWFFcli: true, IsNferSplit: true}
+
+	if err := ProcessAdifCommand(adifParams); err != nil {
+		t.Fatalf("ProcessAdifCommand() unexpected error: %v", err)
+	}
+	for _, ref := range []string{"ONFF-0259", "ONFF-0001"} {
+		if _, err := os.Stat(filepath.Join(directory, "s_"+ref+".adi")); err != nil {
+			t.Errorf("ProcessAdifCommand() didn't generate the file for %s: %v", ref, err)
+		}
+	}
+
+	//The files per reference are checked for overwrite
+	if err := ProcessAdifCommand(adifParams); err == nil {
+		t.Errorf("ProcessAdifCommand() should fail when the files per reference exist")
+	}
+	adifParams.IsOverwrite = true
+	if err := ProcessAdifCommand(adifParams); err != nil {
+		t.Errorf("ProcessAdifCommand() unexpected error with overwrite: %v", err)
+	}
+}
+
+func TestProcessAdifCommand_nferRefFields(t *testing.T) {
+	directory, err := ioutil.TempDir("", "FLEcli-nfer-ref")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	inputFilename := filepath.Join(directory, "nfer.txt")
+	ioutil.WriteFile(inputFilename, []byte("mycall on4kjm/p\noperator on4kjm\nmywwff onff-0259, onff-0001\nmypota on-00259, on-00001\n"+
+		"date 2020-05-24 40m cw\n1310 ik5zve\n1312 dl1aa\n"), 0644)
+
+	//MY_WWFF_REF holds a single reference
+	if err := ProcessAdifCommand(AdifParams{InputFilename: inputFilename, IsWWFFcli: true, IsPOTAcli: true, AdifVersion: "3.1.4"}); err == nil {
+		t.Errorf("ProcessAdifCommand() should fail for several MY-WWFF references")
+	}
+
+	tests := []struct {
+		name       string
+		adifParams AdifParams
+		outputGlob string
+	}{
+		{"Split per reference", AdifParams{InputFilename: inputFilename, IsWWFFcli: true, IsPOTAcli: true, IsNferSplit: true, AdifVersion: "3.1.4", IsOverwrite: true}, "nfer_*.adi"},
+		{"POTA reference list", AdifParams{InputFilename: inputFilename, IsPOTAcli: true, AdifVersion: "3.1.4", IsOverwrite: true}, "nfer.adi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ProcessAdifCommand(tt.adifParams); err != nil {
+				t.Fatalf("ProcessAdifCommand() unexpected error: %v", err)
+			}
+			outputFilenames, _ := filepath.Glob(filepath.Join(directory, tt.outputGlob))
+			if len(outputFilenames) == 0 {
+				t.Fatalf("ProcessAdifCommand() didn't generate %s", tt.outputGlob)
+			}
+			for _, outputFilename := range outputFilenames {
+				output, _ := ioutil.ReadFile(outputFilename)
+				adifFile, err := ReadAdif(strings.NewReader(string(output)))
+				if err != nil {
+					t.Fatalf("ReadAdif() unexpected error for %s: %v", outputFilename, err)
+				}
+				if diagnostics := ValidateAdif(adifFile); len(diagnostics) != 0 {
+					t.Errorf("ValidateAdif() unexpected diagnostics for %s: %v", outputFilename, diagnostics)
+				}
+			}
+		})
+	}
+}
+
+func Test_buildNferLogs(t *testing.T) {
+	sampleLog := []LogLine{
+		{MyCall: "K1ABC", MyPOTA: "K-1234,K-4567", MyWWFF: "KFF-1234", Call: "W1AW"},
+		{MyCall: "K1ABC", MyPOTA: "K-1234,K-4567", MyWWFF: "KFF-1234", Call: "N1MM"},
+	}
+
+	expectedNferLogs := []outputLog{
+		{reference: "K-1234", outputFilename: "/tmp/log_K-1234.adi", log: []LogLine{
+			{MyCall: "K1ABC", MyPOTA: "K-1234", MyWWFF: "KFF-1234", Call: "W1AW"},
+			{MyCall: "K1ABC", MyPOTA: "K-1234", MyWWFF: "KFF-1234", Call: "N1MM"}}},
+		{reference: "K-4567", outputFilename: "/tmp/log_K-4567.adi", log: []LogLine{
+			{MyCall: "K1ABC", MyPOTA: "K-4567", MyWWFF: "KFF-1234", Call: "W1AW"},
+			{MyCall: "K1ABC", MyPOTA: "K-4567", MyWWFF: "KFF-1234", Call: "N1MM"}}},
+	}
+
+	gotNferLogs := buildNferLogs(sampleLog, AdifParams{IsPOTAcli: true}, "/tmp/log.adi")
+	if !reflect.DeepEqual(gotNferLogs, expectedNferLogs) {
+		t.Errorf("buildNferLogs() = %v, want %v", gotNferLogs, expectedNferLogs)
+	}
+
+	//The source log must not be altered
+	if sampleLog[0].MyPOTA != "K-1234,K-4567" {
+		t.Errorf("buildNferLogs() altered the source log (%s)", sampleLog[0].MyPOTA)
+	}
+}
+
+func Test_isWwffRefDropped(t *testing.T) {
+	nferLog := []LogLine{{MyCall: "ON4KJM/P", MyPOTA: "ON-00259", MyWWFF: "ONFF-0259,ONFF-0001", Call: "IK5ZVE"}}
+	singleRefLog := []LogLine{{MyCall: "ON4KJM/P", MyPOTA: "ON-00259", MyWWFF: "ONFF-0259", Call: "IK5ZVE"}}
+	tests := []struct {
+		name       string
+		log        []LogLine
+		adifParams AdifParams
+		want       bool
+	}{
+		{"POTA file of an n-fer split", nferLog, AdifParams{IsWWFFcli: true, IsPOTAcli: true, IsNferSplit: true, AdifVersion: "3.1.4"}, true},
+		{"Single WWFF reference", singleRefLog, AdifParams{IsWWFFcli: true, IsPOTAcli: true, AdifVersion: "3.1.4"}, false},
+		{"SIG fields", nferLog, AdifParams{IsWWFFcli: true, IsPOTAcli: true, IsNferSplit: true}, false},
+		{"Not a WWFF file", nferLog, AdifParams{IsPOTAcli: true, AdifVersion: "3.1.4"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isWwffRefDropped(tt.log, tt.adifParams); got != tt.want {
+				t.Errorf("isWwffRefDropped() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//Documentation of the ADI format: https://adif.org/314/ADIF_314.htm#ADI_File_Format
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+//AdifField is a data specifier of an ADIF file: <NAME:LENGTH:TYPE>value
+type AdifField struct {
+	//Name is the field name, in uppercase
+	Name  string
+	Value string
+	//Type is the data type indicator, in uppercase. It is empty if not specified.
+	Type string
+	//Line and Column locate the tag of the field in the file (starting at 1)
+	Line   int
+	Column int
+}
+
+//AdifRecord is the list of the fields of a QSO (or of the header), in the order of the file
+type AdifRecord []AdifField
+
+//Get returns the value of the named field (case insensitive). It is empty if the field is not present.
+func (record AdifRecord) Get(name string) string {
+	for _, field := range record {
+		if strings.EqualFold(field.Name, name) {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+//AdifFile is the content of an ADIF file
+type AdifFile struct {
+	//Preamble is the free text starting the header
+	Preamble string
+	Header   AdifRecord
+	Records  []AdifRecord
+}
+
+//adifScanner keeps track of the line and column while going through the ADIF text
+type adifScanner struct {
+	text      string
+	line      int
+	lineStart int
+	scanned   int
+}
+
+//location returns the line and column of the offset (which can't be before the previously requested one)
+func (scanner *adifScanner) location(offset int) (line, column int) {
+	for ; scanner.scanned < offset; scanner.scanned++ {
+		if scanner.text[scanner.scanned] == '\n' {
+			scanner.line++
+			scanner.lineStart = scanner.scanned + 1
+		}
+	}
+	return scanner.line + 1, offset - scanner.lineStart + 1
+}
+
+//ReadAdif parses an ADIF file (ADI format). The field names and type indicators are case insensitive.
+//The header is optional: if present, it is ended by <EOH>. Each record is ended by <EOR>.
+func ReadAdif(reader io.Reader) (adifFile AdifFile, err error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return AdifFile{}, err
+	}
+	text := string(data)
+	scanner := adifScanner{text: text}
+
+	var record AdifRecord
+	position := 0
+	for {
+		tagStart := strings.IndexByte(text[position:], '<')
+		if tagStart < 0 {
+			break
+		}
+		tagStart += position
+		line, column := scanner.location(tagStart)
+		tagEnd := strings.IndexByte(text[tagStart:], '>')
+		if tagEnd < 0 {
+			return AdifFile{}, fmt.Errorf("Unterminated tag at line %d, column %d", line, column)
+		}
+		tagEnd += tagStart
+		tagText := text[tagStart+1 : tagEnd]
+		tag := strings.Split(tagText, ":")
+		name := strings.ToUpper(strings.TrimSpace(tag[0]))
+		position = tagEnd + 1
+
+		switch {
+		case name == "EOH" && len(tag) == 1:
+			if len(adifFile.Records) != 0 || adifFile.Header != nil {
+				return AdifFile{}, fmt.Errorf("Unexpected <EOH> at line %d, column %d", line, column)
+			}
+			//The header starts with free text unless the file starts with a tag
+			if text[0] != '<' {
+				adifFile.Preamble = strings.TrimSpace(text[:strings.IndexByte(text, '<')])
+			}
+			adifFile.Header = append(AdifRecord{}, record...)
+			record = nil
+		case name == "EOR" && len(tag) == 1:
+			adifFile.Records = append(adifFile.Records, record)
+			record = nil
+		default:
+			//<NAME:LENGTH> or <NAME:LENGTH:TYPE>
+			if len(tag) < 2 || len(tag) > 3 || name == "" {
+				return AdifFile{}, fmt.Errorf("Invalid tag <%s> at line %d, column %d", tagText, line, column)
+			}
+			length, err := strconv.Atoi(strings.TrimSpace(tag[1]))
+			if err != nil || length < 0 {
+				return AdifFile{}, fmt.Errorf("Invalid length in tag <%s> at line %d, column %d", tagText, line, column)
+			}
+			if position+length > len(text) {
+				return AdifFile{}, fmt.Errorf("Value of tag <%s> at line %d, column %d exceeds the end of the file", tagText, line, column)
+			}
+			field := AdifField{Name: name, Value: text[position : position+length], Line: line, Column: column}
+			if len(tag) == 3 {
+				field.Type = strings.ToUpper(strings.TrimSpace(tag[2]))
+			}
+			record = append(record, field)
+			position += length
+		}
+	}
+
+	if len(record) != 0 {
+		return AdifFile{}, fmt.Errorf("Missing <EOR> after the last record")
+	}
+	return adifFile, nil
+}
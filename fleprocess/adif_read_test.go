@@ -0,0 +1,132 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAdif(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantAdifFile AdifFile
+		wantErr      bool
+	}{
+		{
+			"Header and records",
+			"ADIF Export for Fast Log Entry by DF3CB\n<PROGRAMID:3>FLE\n<EOH>\n" +
+				"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>S57LC <EOR>\n" +
+				"<call:5>ON4LY<Comment:9:s>QRP <tnx><eor>\n",
+			AdifFile{
+				Preamble: "ADIF Export for Fast Log Entry by DF3CB",
+				Header:   AdifRecord{{Name: "PROGRAMID", Value: "FLE", Line: 2, Column: 1}},
+				Records: []AdifRecord{
+					{{Name: "STATION_CALLSIGN", Value: "ON4KJM/P", Line: 4, Column: 1}, {Name: "CALL", Value: "S57LC", Line: 4, Column: 30}},
+					{{Name: "CALL", Value: "ON4LY", Line: 5, Column: 1}, {Name: "COMMENT", Value: "QRP <tnx>", Type: "S", Line: 5, Column: 14}},
+				},
+			},
+			false,
+		},
+		{
+			"No header",
+			"<CALL:5>S57LC<EOR>",
+			AdifFile{Records: []AdifRecord{{{Name: "CALL", Value: "S57LC", Line: 1, Column: 1}}}},
+			false,
+		},
+		{
+			"Empty header",
+			"<EOH><CALL:0><EOR>",
+			AdifFile{Header: AdifRecord{}, Records: []AdifRecord{{{Name: "CALL", Value: "", Line: 1, Column: 6}}}},
+			false,
+		},
+		{
+			"Empty file",
+			"",
+			AdifFile{},
+			false,
+		},
+		{
+			"Unterminated tag",
+			"<CALL:5>S57LC<EOR",
+			AdifFile{},
+			true,
+		},
+		{
+			"Invalid tag",
+			"<CALL:5:S:X>S57LC<EOR>",
+			AdifFile{},
+			true,
+		},
+		{
+			"Invalid length",
+			"<CALL:x>S57LC<EOR>",
+			AdifFile{},
+			true,
+		},
+		{
+			"Value beyond the end of the file",
+			"<CALL:15>S57LC",
+			AdifFile{},
+			true,
+		},
+		{
+			"Header after a record",
+			"<CALL:5>S57LC<EOR><EOH>",
+			AdifFile{},
+			true,
+		},
+		{
+			"Missing EOR",
+			"<CALL:5>S57LC<EOR><CALL:5>ON4LY",
+			AdifFile{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAdifFile, err := ReadAdif(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadAdif() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotAdifFile, tt.wantAdifFile) {
+				t.Errorf("ReadAdif() = %v, want %v", gotAdifFile, tt.wantAdifFile)
+			}
+		})
+	}
+}
+
+func TestAdifRecord_Get(t *testing.T) {
+	record := AdifRecord{{Name: "CALL", Value: "S57LC"}, {Name: "MODE", Value: "CW"}, {Name: "CALL", Value: "ON4LY"}}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"MODE", "CW"},
+		{"call", "S57LC"},
+		{"BAND", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := record.Get(tt.name); got != tt.want {
+				t.Errorf("AdifRecord.Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
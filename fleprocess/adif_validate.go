@@ -0,0 +1,451 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//Documentation of the ADIF fields and data types: https://adif.org/314/ADIF_314.htm
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//adifValueCheck verifies the value of a field. It returns the problem found (empty if the value is valid).
+type adifValueCheck func(value string) (problem string)
+
+//adifTypeChecks holds the checks of the data type indicators
+var adifTypeChecks = map[string]adifValueCheck{
+	"B": checkAdifBoolean,
+	"N": checkAdifNumber,
+	"D": checkAdifDate,
+	"T": checkAdifTime,
+	"S": checkAdifString,
+	"I": checkAdifAny,
+	"M": checkAdifMultilineString,
+	"G": checkAdifAny,
+	"E": checkAdifAny,
+	"L": checkAdifLocation,
+}
+
+//adifHeaderFields holds the checks of the header fields (USERDEFn fields excepted)
+var adifHeaderFields = map[string]adifValueCheck{
+	"ADIF_VER":          checkAdifString,
+	"CREATED_TIMESTAMP": checkAdifTimestamp,
+	"PROGRAMID":         checkAdifString,
+	"PROGRAMVERSION":    checkAdifString,
+}
+
+var qslRcvdValues = checkAdifEnumeration("Y", "N", "R", "I", "V")
+var qslSentValues = checkAdifEnumeration("Y", "N", "R", "Q", "I")
+var qslViaValues = checkAdifEnumeration("B", "D", "E", "M")
+
+//adifRecordFields holds the checks of the QSO fields supported in an ADI file
+var adifRecordFields = map[string]adifValueCheck{
+	"ADDRESS":          checkAdifMultilineString,
+	"AGE":              checkAdifNumber,
+	"A_INDEX":          checkAdifNumber,
+	"ANT_AZ":           checkAdifNumber,
+	"ANT_EL":           checkAdifNumber,
+	"ANT_PATH":         checkAdifEnumeration("G", "O", "S", "L"),
+	"BAND":             checkAdifBand,
+	"BAND_RX":          checkAdifBand,
+	"CALL":             checkAdifString,
+	"CHECK":            checkAdifString,
+	"CLASS":            checkAdifString,
+	"CNTY":             checkAdifString,
+	"COMMENT":          checkAdifString,
+	"CONT":             checkAdifEnumeration("NA", "SA", "EU", "AF", "OC", "AS", "AN"),
+	"CONTACTED_OP":     checkAdifString,
+	"CONTEST_ID":       checkAdifString,
+	"COUNTRY":          checkAdifString,
+	"CQZ":              checkAdifIntegerRange(1, 40),
+	"DISTANCE":         checkAdifNumber,
+	"DXCC":             checkAdifIntegerRange(0, 999),
+	"EMAIL":            checkAdifString,
+	"EQ_CALL":          checkAdifString,
+	"EQSL_QSLRDATE":    checkAdifDate,
+	"EQSL_QSLSDATE":    checkAdifDate,
+	"EQSL_QSL_RCVD":    qslRcvdValues,
+	"EQSL_QSL_SENT":    qslSentValues,
+	"FREQ":             checkAdifNumber,
+	"FREQ_RX":          checkAdifNumber,
+	"GRIDSQUARE":       checkAdifGridSquare,
+	"IOTA":             checkAdifString,
+	"ITUZ":             checkAdifIntegerRange(1, 90),
+	"K_INDEX":          checkAdifIntegerRange(0, 9),
+	"LAT":              checkAdifLocation,
+	"LON":              checkAdifLocation,
+	"LOTW_QSLRDATE":    checkAdifDate,
+	"LOTW_QSLSDATE":    checkAdifDate,
+	"LOTW_QSL_RCVD":    qslRcvdValues,
+	"LOTW_QSL_SENT":    qslSentValues,
+	"MODE":             checkAdifMode,
+	"MY_CITY":          checkAdifString,
+	"MY_CNTY":          checkAdifString,
+	"MY_COUNTRY":       checkAdifString,
+	"MY_CQ_ZONE":       checkAdifIntegerRange(1, 40),
+	"MY_DXCC":          checkAdifIntegerRange(0, 999),
+	"MY_GRIDSQUARE":    checkAdifGridSquare,
+	"MY_IOTA":          checkAdifString,
+	"MY_ITU_ZONE":      checkAdifIntegerRange(1, 90),
+	"MY_LAT":           checkAdifLocation,
+	"MY_LON":           checkAdifLocation,
+	"MY_NAME":          checkAdifString,
+	"MY_POTA_REF":      checkAdifReference(ValidatePotaList),
+	"MY_RIG":           checkAdifString,
+	"MY_SIG":           checkAdifString,
+	"MY_SIG_INFO":      checkAdifString,
+	"MY_SOTA_REF":      checkAdifReference(ValidateSota),
+	"MY_STATE":         checkAdifString,
+	"MY_STREET":        checkAdifString,
+	"MY_WWFF_REF":      checkAdifReference(ValidateWwff),
+	"NAME":             checkAdifString,
+	"NOTES":            checkAdifMultilineString,
+	"OPERATOR":         checkAdifString,
+	"OWNER_CALLSIGN":   checkAdifString,
+	"PFX":              checkAdifString,
+	"POTA_REF":         checkAdifReference(ValidatePotaList),
+	"PRECEDENCE":       checkAdifString,
+	"PROP_MODE":        checkAdifEnumeration("AS", "AUE", "AUR", "BS", "ECH", "EME", "ES", "F2", "FAI", "GWAVE", "INTERNET", "ION", "IRL", "LOS", "MS", "RPT", "RS", "SAT", "TEP", "TR"),
+	"QSL_RCVD":         qslRcvdValues,
+	"QSL_RCVD_VIA":     qslViaValues,
+	"QSL_SENT":         qslSentValues,
+	"QSL_SENT_VIA":     qslViaValues,
+	"QSL_VIA":          checkAdifString,
+	"QSLMSG":           checkAdifMultilineString,
+	"QSLRDATE":         checkAdifDate,
+	"QSLSDATE":         checkAdifDate,
+	"QSO_COMPLETE":     checkAdifEnumeration("Y", "N", "NIL", "?"),
+	"QSO_DATE":         checkAdifDate,
+	"QSO_DATE_OFF":     checkAdifDate,
+	"QSO_RANDOM":       checkAdifBoolean,
+	"QTH":              checkAdifString,
+	"RST_RCVD":         checkAdifString,
+	"RST_SENT":         checkAdifString,
+	"RX_PWR":           checkAdifNumber,
+	"SFI":              checkAdifIntegerRange(0, 300),
+	"SIG":              checkAdifString,
+	"SIG_INFO":         checkAdifString,
+	"SOTA_REF":         checkAdifReference(ValidateSota),
+	"SRX":              checkAdifIntegerRange(0, -1),
+	"SRX_STRING":       checkAdifString,
+	"STATE":            checkAdifString,
+	"STATION_CALLSIGN": checkAdifString,
+	"STX":              checkAdifIntegerRange(0, -1),
+	"STX_STRING":       checkAdifString,
+	"SUBMODE":          checkAdifString,
+	"SWL":              checkAdifBoolean,
+	"TEN_TEN":          checkAdifIntegerRange(1, -1),
+	"TIME_OFF":         checkAdifTime,
+	"TIME_ON":          checkAdifTime,
+	"TX_PWR":           checkAdifNumber,
+	"WEB":              checkAdifString,
+	"WWFF_REF":         checkAdifReference(ValidateWwff),
+}
+
+//adifMandatoryFields are the fields required to describe a QSO (the band can be replaced by the frequency)
+var adifMandatoryFields = []string{"CALL", "QSO_DATE", "TIME_ON", "MODE"}
+
+//ProcessValidateAdifCommand checks an ADIF file and lists the problems found. It is called from the COBRA interface.
+//An error is returned if the file can't be read or if it contains errors (warnings are only listed).
+func ProcessValidateAdifCommand(inputFilename string) error {
+	file, err := os.Open(inputFilename)
+	if err != nil {
+		return fmt.Errorf("failed opening file: %s", err)
+	}
+	defer file.Close()
+
+	adifFile, err := ReadAdif(file)
+	if err != nil {
+		return fmt.Errorf("Invalid ADIF file: %s", err)
+	}
+
+	diagnostics := ValidateAdif(adifFile)
+	for _, diagnostic := range diagnostics {
+		fmt.Println(diagnostic)
+	}
+
+	errorCount := 0
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity >= SeverityError {
+			errorCount++
+		}
+	}
+	fmt.Printf("\nChecked %d QSOs: %d errors, %d warnings\n", len(adifFile.Records), errorCount, len(diagnostics)-errorCount)
+	if errorCount != 0 {
+		return fmt.Errorf("The ADIF file contains errors")
+	}
+	return nil
+}
+
+//ValidateAdif checks the header and the records of an ADIF file: the field names, their data type and enumerated values,
+//and the fields required for a QSO. Unknown fields and duplicated fields are reported as warnings.
+func ValidateAdif(adifFile AdifFile) (diagnostics []Diagnostic) {
+	userFields := map[string]bool{}
+	for _, field := range adifFile.Header {
+		check, isKnown := adifHeaderFields[field.Name]
+		if strings.HasPrefix(field.Name, "USERDEF") {
+			//The value of a USERDEFn field is the name of the user defined field (possibly followed by its values)
+			userFields[strings.ToUpper(strings.SplitN(field.Value, ",", 2)[0])] = true
+			check, isKnown = checkAdifString, true
+		}
+		diagnostics = append(diagnostics, validateAdifField(field, check, isKnown)...)
+	}
+
+	for i, record := range adifFile.Records {
+		recordName := fmt.Sprintf("QSO #%d", i+1)
+		if call := record.Get("CALL"); call != "" {
+			recordName = fmt.Sprintf("QSO #%d (%s)", i+1, call)
+		}
+
+		foundFields := map[string]bool{}
+		for _, field := range record {
+			if foundFields[field.Name] {
+				diagnostics = append(diagnostics, adifDiagnostic(field, CodeDuplicateField, SeverityWarning, "%s: duplicate field %s (only the first one is used)", recordName, field.Name))
+				continue
+			}
+			foundFields[field.Name] = true
+
+			check, isKnown := adifRecordFields[field.Name]
+			//Application defined fields are free
+			if strings.HasPrefix(field.Name, "APP_") || userFields[field.Name] {
+				check, isKnown = nil, true
+			}
+			diagnostics = append(diagnostics, validateAdifField(field, check, isKnown)...)
+		}
+
+		//The missing fields are reported at the first field of the record
+		var firstField AdifField
+		if len(record) != 0 {
+			firstField = record[0]
+		}
+		for _, name := range adifMandatoryFields {
+			if record.Get(name) == "" {
+				diagnostics = append(diagnostics, adifDiagnostic(firstField, CodeMissingField, SeverityError, "%s: missing %s", recordName, name))
+			}
+		}
+		if record.Get("BAND") == "" && record.Get("FREQ") == "" {
+			diagnostics = append(diagnostics, adifDiagnostic(firstField, CodeMissingField, SeverityError, "%s: missing BAND or FREQ", recordName))
+		}
+		if mode, submode := strings.ToUpper(record.Get("MODE")), strings.ToUpper(record.Get("SUBMODE")); submode != "" && checkAdifMode(mode) == "" && !isAdifSubmode(mode, submode) {
+			diagnostics = append(diagnostics, adifDiagnostic(firstField, CodeInvalidValue, SeverityError, "%s: %s is not a submode of %s", recordName, submode, mode))
+		}
+		if problem := checkAdifFrequencyInBand(record.Get("FREQ"), record.Get("BAND")); problem != "" {
+			diagnostics = append(diagnostics, adifDiagnostic(firstField, CodeInvalidValue, SeverityError, "%s: %s", recordName, problem))
+		}
+	}
+	return diagnostics
+}
+
+//validateAdifField checks the data type indicator and the value of a field. The value of a field without a check
+//is only verified against its data type indicator, if any.
+func validateAdifField(field AdifField, check adifValueCheck, isKnown bool) (diagnostics []Diagnostic) {
+	if !isKnown {
+		diagnostics = append(diagnostics, adifDiagnostic(field, CodeUnknownField, SeverityWarning, "Unknown field %s", field.Name))
+	}
+	if field.Type != "" {
+		typeCheck, isKnownType := adifTypeChecks[field.Type]
+		if !isKnownType {
+			return append(diagnostics, adifDiagnostic(field, CodeInvalidDataType, SeverityError, "Invalid data type indicator [%s] for %s", field.Type, field.Name))
+		}
+		if check == nil {
+			check = typeCheck
+		}
+	}
+	if check == nil || field.Value == "" {
+		return diagnostics
+	}
+	if problem := check(field.Value); problem != "" {
+		diagnostics = append(diagnostics, adifDiagnostic(field, CodeInvalidValue, SeverityError, "Invalid %s value [%s]: %s", field.Name, field.Value, problem))
+	}
+	return diagnostics
+}
+
+//adifDiagnostic creates a diagnostic located at the tag of the field
+func adifDiagnostic(field AdifField, code DiagnosticCode, severity Severity, format string, a ...interface{}) Diagnostic {
+	diagnostic := *newDiagnostic(code, format, a...)
+	diagnostic.Severity = severity
+	diagnostic.Line = field.Line
+	return diagnostic.at(field.Column, 0)
+}
+
+//checkAdifAny accepts any value
+func checkAdifAny(value string) string {
+	return ""
+}
+
+//checkAdifString accepts the printable ASCII characters
+func checkAdifString(value string) string {
+	for _, char := range value {
+		if char < 32 || char > 126 {
+			return "only printable ASCII characters are allowed"
+		}
+	}
+	return ""
+}
+
+//checkAdifMultilineString accepts the printable ASCII characters and line breaks
+func checkAdifMultilineString(value string) string {
+	return checkAdifString(strings.NewReplacer("\r", "", "\n", "").Replace(value))
+}
+
+//checkAdifBoolean accepts Y or N
+func checkAdifBoolean(value string) string {
+	if !strings.EqualFold(value, "Y") && !strings.EqualFold(value, "N") {
+		return "Y or N expected"
+	}
+	return ""
+}
+
+var adifNumberRegexp = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)$`)
+
+//checkAdifNumber accepts a decimal number
+func checkAdifNumber(value string) string {
+	if !adifNumberRegexp.MatchString(value) {
+		return "number expected"
+	}
+	return ""
+}
+
+//checkAdifIntegerRange creates a check of an integer between min and max (no maximum if max is negative)
+func checkAdifIntegerRange(min, max int) adifValueCheck {
+	return func(value string) string {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return "integer expected"
+		}
+		if number < min || (max >= 0 && number > max) {
+			if max < 0 {
+				return fmt.Sprintf("integer greater or equal to %d expected", min)
+			}
+			return fmt.Sprintf("integer between %d and %d expected", min, max)
+		}
+		return ""
+	}
+}
+
+//checkAdifDate accepts a date in YYYYMMDD format, from 1930
+func checkAdifDate(value string) string {
+	date, err := time.Parse("20060102", value)
+	if err != nil || date.Year() < 1930 {
+		return "date (YYYYMMDD) expected"
+	}
+	return ""
+}
+
+//checkAdifTime accepts a time in HHMM or HHMMSS format
+func checkAdifTime(value string) string {
+	layout := "1504"
+	if len(value) == 6 {
+		layout = "150405"
+	}
+	if _, err := time.Parse(layout, value); err != nil {
+		return "time (HHMM or HHMMSS) expected"
+	}
+	return ""
+}
+
+//checkAdifTimestamp accepts a date and time in YYYYMMDD HHMMSS format
+func checkAdifTimestamp(value string) string {
+	if _, err := time.Parse("20060102 150405", value); err != nil {
+		return "timestamp (YYYYMMDD HHMMSS) expected"
+	}
+	return ""
+}
+
+var adifLocationRegexp = regexp.MustCompile(`^[NSEWnsew](\d{3}) (\d{2})\.\d{3}$`)
+
+//checkAdifLocation accepts a latitude or longitude in XDDD MM.MMM format
+func checkAdifLocation(value string) string {
+	elements := adifLocationRegexp.FindStringSubmatch(value)
+	if elements == nil {
+		return "location (XDDD MM.MMM) expected"
+	}
+	degrees, _ := strconv.Atoi(elements[1])
+	minutes, _ := strconv.Atoi(elements[2])
+	if degrees > 180 || minutes > 59 {
+		return "location (XDDD MM.MMM) expected"
+	}
+	return ""
+}
+
+var adifGridSquareRegexp = regexp.MustCompile(`^[A-Ra-r]{2}(\d{2}([A-Xa-x]{2}(\d{2})?)?)?$`)
+
+//checkAdifGridSquare accepts a 2, 4, 6 or 8 characters Maidenhead locator
+func checkAdifGridSquare(value string) string {
+	if !adifGridSquareRegexp.MatchString(value) {
+		return "Maidenhead locator expected"
+	}
+	return ""
+}
+
+//checkAdifEnumeration creates a check of a value among the listed ones (case insensitive)
+func checkAdifEnumeration(values ...string) adifValueCheck {
+	return func(value string) string {
+		for _, enumerated := range values {
+			if strings.EqualFold(value, enumerated) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("one of %s expected", strings.Join(values, ", "))
+	}
+}
+
+//checkAdifBand accepts the ADIF bands
+func checkAdifBand(value string) string {
+	if isBand, _, _, _ := IsBand(value); !isBand {
+		return "unknown band"
+	}
+	return ""
+}
+
+//checkAdifMode accepts the ADIF modes. The submodes used as mode are reported as such.
+func checkAdifMode(value string) string {
+	value = strings.ToUpper(value)
+	if _, isAdifMode := adifModes[value]; isAdifMode {
+		return ""
+	}
+	if mode, isMode := fleModes[value]; isMode {
+		return fmt.Sprintf("%s is a submode of %s", value, mode.AdifMode)
+	}
+	return "unknown mode"
+}
+
+//checkAdifReference creates a check based on the validation of a reference
+func checkAdifReference(validate func(string) (string, *Diagnostic)) adifValueCheck {
+	return func(value string) string {
+		if _, diagnostic := validate(value); diagnostic != nil {
+			return diagnostic.Message
+		}
+		return ""
+	}
+}
+
+//checkAdifFrequencyInBand verifies that the frequency (in MHz) is within the band, if both are valid
+func checkAdifFrequencyInBand(frequency, band string) string {
+	isBand, lowerLimit, upperLimit, _ := IsBand(band)
+	if !isBand || checkAdifNumber(frequency) != "" {
+		return ""
+	}
+	if value, _ := strconv.ParseFloat(frequency, 64); value < lowerLimit || value > upperLimit {
+		return fmt.Sprintf("frequency %s MHz is outside the %s band", frequency, strings.ToLower(band))
+	}
+	return ""
+}
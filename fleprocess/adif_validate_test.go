@@ -0,0 +1,174 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateAdif(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantDiagnostics []Diagnostic
+	}{
+		{
+			"Valid file",
+			"<ADIF_VER:5>3.1.0 <CREATED_TIMESTAMP:15>20200524 131000 <USERDEF1:8:N>EPC_NUMB <EOH>\n" +
+				"<CALL:5>S57LC<QSO_DATE:8>20200524<TIME_ON:6>131045<BAND:3>20M<FREQ:6>14.045<MODE:2>cw<QSL_SENT:1>y" +
+				"<MY_SOTA_REF:9>ON/ON-001<MY_WWFF_REF:9>ONFF-0258<POTA_REF:15>ON-00001,K-1234<LAT:11>N050 48.117<SRX:3>001" +
+				"<APP_EQSL_QTH_NICKNAME:4>Test<EPC_NUMB:4>1234<EOR>\n",
+			nil,
+		},
+		{
+			"Invalid values",
+			"<CALL:5>S57LC<QSO_DATE:8>20201350<TIME_ON:4>2561<BAND:3>21m<MODE:3>FOO<QSL_SENT:1>X<CQZ:2>41\n" +
+				"<GRIDSQUARE:4>ZZ20<SOTA_REF:5>ON001<LON:11>W200 00.000<TX_PWR:2>5W<QSO_RANDOM:3>Yes<EOR>",
+			[]Diagnostic{
+				{Line: 1, Column: 14, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid QSO_DATE value [20201350]: date (YYYYMMDD) expected"},
+				{Line: 1, Column: 34, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid TIME_ON value [2561]: time (HHMM or HHMMSS) expected"},
+				{Line: 1, Column: 49, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid BAND value [21m]: unknown band"},
+				{Line: 1, Column: 60, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid MODE value [FOO]: unknown mode"},
+				{Line: 1, Column: 71, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid QSL_SENT value [X]: one of Y, N, R, Q, I expected"},
+				{Line: 1, Column: 84, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid CQZ value [41]: integer between 1 and 40 expected"},
+				{Line: 2, Column: 1, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid GRIDSQUARE value [ZZ20]: Maidenhead locator expected"},
+				{Line: 2, Column: 19, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid SOTA_REF value [ON001]: [ON001] is an invalid SOTA reference"},
+				{Line: 2, Column: 36, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid LON value [W200 00.000]: location (XDDD MM.MMM) expected"},
+				{Line: 2, Column: 55, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid TX_PWR value [5W]: number expected"},
+				{Line: 2, Column: 67, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid QSO_RANDOM value [Yes]: Y or N expected"},
+			},
+		},
+		{
+			"Unknown, duplicate and typed fields",
+			"<PROGRAMID:3:X>FLE<EOH><CALL:5>S57LC<CALL:5>ON4LY<QSO_DATE:8>20200524<TIME_ON:4>1310<BAND:3>20m<MODE:2>CW<FOO:3:N>1.5<BAR:3:N>1,5<EOR>",
+			[]Diagnostic{
+				{Line: 1, Column: 1, Code: CodeInvalidDataType, Severity: SeverityError, Message: "Invalid data type indicator [X] for PROGRAMID"},
+				{Line: 1, Column: 37, Code: CodeDuplicateField, Severity: SeverityWarning, Message: "QSO #1 (S57LC): duplicate field CALL (only the first one is used)"},
+				{Line: 1, Column: 106, Code: CodeUnknownField, Severity: SeverityWarning, Message: "Unknown field FOO"},
+				{Line: 1, Column: 118, Code: CodeUnknownField, Severity: SeverityWarning, Message: "Unknown field BAR"},
+				{Line: 1, Column: 118, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid BAR value [1,5]: number expected"},
+			},
+		},
+		{
+			"Modes and submodes",
+			"<CALL:5>S57LC<QSO_DATE:8>20200524<TIME_ON:4>1310<BAND:3>20m<MODE:3>FT4<EOR>\n" +
+				"<CALL:5>S57LC<QSO_DATE:8>20200524<TIME_ON:4>1310<BAND:3>20m<MODE:3>SSB<SUBMODE:3>FT4<EOR>\n" +
+				"<CALL:5>S57LC<QSO_DATE:8>20200524<TIME_ON:4>1310<BAND:3>20m<MODE:4>MFSK<SUBMODE:3>ft4<EOR>\n" +
+				"<CALL:5>S57LC<QSO_DATE:8>20200524<TIME_ON:4>1310<BAND:3>20m<MODE:7>DYNAMIC<SUBMODE:7>VARA HF<EOR>",
+			[]Diagnostic{
+				{Line: 1, Column: 60, Code: CodeInvalidValue, Severity: SeverityError, Message: "Invalid MODE value [FT4]: FT4 is a submode of MFSK"},
+				{Line: 2, Column: 1, Code: CodeInvalidValue, Severity: SeverityError, Message: "QSO #2 (S57LC): FT4 is not a submode of SSB"},
+			},
+		},
+		{
+			"Missing fields and frequency outside the band",
+			"<CALL:5>S57LC<BAND:3>40m<FREQ:6>14.045<EOR><EOR>",
+			[]Diagnostic{
+				{Line: 1, Column: 1, Code: CodeMissingField, Severity: SeverityError, Message: "QSO #1 (S57LC): missing QSO_DATE"},
+				{Line: 1, Column: 1, Code: CodeMissingField, Severity: SeverityError, Message: "QSO #1 (S57LC): missing TIME_ON"},
+				{Line: 1, Column: 1, Code: CodeMissingField, Severity: SeverityError, Message: "QSO #1 (S57LC): missing MODE"},
+				{Line: 1, Column: 1, Code: CodeInvalidValue, Severity: SeverityError, Message: "QSO #1 (S57LC): frequency 14.045 MHz is outside the 40m band"},
+				{Code: CodeMissingField, Severity: SeverityError, Message: "QSO #2: missing CALL"},
+				{Code: CodeMissingField, Severity: SeverityError, Message: "QSO #2: missing QSO_DATE"},
+				{Code: CodeMissingField, Severity: SeverityError, Message: "QSO #2: missing TIME_ON"},
+				{Code: CodeMissingField, Severity: SeverityError, Message: "QSO #2: missing MODE"},
+				{Code: CodeMissingField, Severity: SeverityError, Message: "QSO #2: missing BAND or FREQ"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adifFile, err := ReadAdif(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadAdif() unexpected error: %v", err)
+			}
+			if gotDiagnostics := ValidateAdif(adifFile); !reflect.DeepEqual(gotDiagnostics, tt.wantDiagnostics) {
+				t.Errorf("ValidateAdif() = %v, want %v", gotDiagnostics, tt.wantDiagnostics)
+			}
+		})
+	}
+}
+
+//The ADIF files generated by FLEcli are valid
+func TestValidateAdif_generatedFiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		adifParams AdifParams
+	}{
+		{"WWFF and SOTA", "../test/data/sample_wwff_sota.txt", AdifParams{IsWWFFcli: true, IsSOTAcli: true}},
+		{"WWFF with ADIF 3.1.4", "../test/data/ON4KJM@ONFF-025920200524.txt", AdifParams{IsWWFFcli: true, AdifVersion: "3.1.4"}},
+		{"Contest", "../test/data/sample_contest_ru.txt", AdifParams{}},
+		{"DXpedition", "../test/data/sample_dxpedition.txt", AdifParams{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadedLog, isLoadedOK := LoadFile(tt.filename, true)
+			if !isLoadedOK {
+				t.Fatalf("LoadFile() failed for %s", tt.filename)
+			}
+			adifFile, err := ReadAdif(strings.NewReader(strings.Join(buildAdif(loadedLog, tt.adifParams), "\n")))
+			if err != nil {
+				t.Fatalf("ReadAdif() unexpected error: %v", err)
+			}
+			if len(adifFile.Records) != len(loadedLog) {
+				t.Errorf("ReadAdif() read %d records, want %d", len(adifFile.Records), len(loadedLog))
+			}
+			if diagnostics := ValidateAdif(adifFile); len(diagnostics) != 0 {
+				t.Errorf("ValidateAdif() unexpected diagnostics: %v", diagnostics)
+			}
+		})
+	}
+}
+
+func TestProcessValidateAdifCommand(t *testing.T) {
+	directory, err := ioutil.TempDir("", "FLEcli-validate-adif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	validFilename := filepath.Join(directory, "valid.adi")
+	ioutil.WriteFile(validFilename, []byte("<CALL:5>S57LC<QSO_DATE:8>20200524<TIME_ON:4>1310<BAND:3>20m<MODE:2>CW<FOO:1>x<EOR>\n"), 0644)
+	invalidFilename := filepath.Join(directory, "invalid.adi")
+	ioutil.WriteFile(invalidFilename, []byte("<CALL:5>S57LC<QSO_DATE:8>20200524<TIME_ON:4>1310<BAND:3>20m<MODE:3>FOO<EOR>\n"), 0644)
+	unreadableFilename := filepath.Join(directory, "unreadable.adi")
+	ioutil.WriteFile(unreadableFilename, []byte("<CALL:5>S57LC<EOR"), 0644)
+
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantErr       bool
+	}{
+		{"Valid file (with a warning)", validFilename, false},
+		{"Invalid value", invalidFilename, true},
+		{"Syntax error", unreadableFilename, true},
+		{"Missing file", filepath.Join(directory, "missing.adi"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ProcessValidateAdifCommand(tt.inputFilename); (err != nil) != tt.wantErr {
+				t.Errorf("ProcessValidateAdifCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,84 +17,225 @@ limitations under the License.
 */
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 )
 
 // OutputAdif generates and writes data in ADIF format
-func OutputAdif(outputFile string, fullLog []LogLine, isWWFF bool, isSOTA bool) {
+func OutputAdif(outputFile string, fullLog []LogLine, adifParams AdifParams) {
 
 	//convert the log data to an in-memory ADIF file
-	adifData := buildAdif(fullLog, isWWFF, isSOTA)
+	adifData := buildAdif(fullLog, adifParams)
 
 	//write to a file
 	writeFile(outputFile, adifData)
 }
 
 // buildAdif creates the adif file in memory ready to be printed
-func buildAdif(fullLog []LogLine, isWWFF bool, isSOTA bool) (adifList []string) {
-	//Print the fixed header
-	adifList = append(adifList, "ADIF Export for Fast Log Entry by DF3CB")
-	adifList = append(adifList, "<PROGRAMID:3>FLE")
-	adifList = append(adifList, "<ADIF_VER:5>3.1.0")
-	adifList = append(adifList, "<EOH>")
-
+func buildAdif(fullLog []LogLine, adifParams AdifParams) (adifList []string) {
+	adifList = adifHeader(adifParams)
 	for _, logLine := range fullLog {
-		var adifLine strings.Builder
-		adifLine.WriteString(adifElement("STATION_CALLSIGN", logLine.MyCall))
-		adifLine.WriteString(adifElement("CALL", logLine.Call))
-		adifLine.WriteString(adifElement("QSO_DATE", adifDate(logLine.Date)))
-		adifLine.WriteString(adifElement("TIME_ON", logLine.Time))
-		adifLine.WriteString(adifElement("BAND", logLine.Band))
-		adifLine.WriteString(adifElement("MODE", logLine.Mode))
-		if logLine.Frequency != "" {
-			adifLine.WriteString(adifElement("FREQ", logLine.Frequency))
-		}
-		adifLine.WriteString(adifElement("RST_SENT", logLine.RSTsent))
-		adifLine.WriteString(adifElement("RST_RCVD", logLine.RSTrcvd))
-		if logLine.Comment != "" {
-			adifLine.WriteString(adifElement("COMMENT", logLine.Comment))
-		}
-		if logLine.OMname != "" {
-			adifLine.WriteString(adifElement("NAME", logLine.OMname))
-		}
-		if logLine.GridLoc != "" {
-			adifLine.WriteString(adifElement("GRIDSQUARE", logLine.GridLoc))
+		if adifRecord, isExportable := buildAdifRecord(logLine, adifParams); isExportable {
+			adifList = append(adifList, adifRecord)
 		}
-		if logLine.QSLmsg != "" {
-			adifLine.WriteString(adifElement("QSLMSG", logLine.QSLmsg))
+	}
+	return adifList
+}
+
+// adifPreamble is the free text starting the header of the generated files
+const adifPreamble = "ADIF Export for Fast Log Entry by DF3CB"
+
+// adifHeader returns the lines of the fixed ADIF header
+func adifHeader(adifParams AdifParams) []string {
+	headerLines := []string{adifPreamble}
+	for _, field := range adifHeaderValues(adifParams) {
+		headerLines = append(headerLines, fmt.Sprintf("<%s:%d>%s", field.Name, len(field.Value), field.Value))
+	}
+	return append(headerLines, "<EOH>")
+}
+
+// adifHeaderValues returns the fields of the ADIF header
+func adifHeaderValues(adifParams AdifParams) AdifRecord {
+	return AdifRecord{
+		{Name: "PROGRAMID", Value: "FLE"},
+		{Name: "ADIF_VER", Value: adifVersion(adifParams)},
+	}
+}
+
+// buildAdifRecord converts a QSO to an ADIF record.
+// A QSO can't be exported without a valid date and calls (isExportable is false).
+func buildAdifRecord(logLine LogLine, adifParams AdifParams) (adifRecord string, isExportable bool) {
+	fields, isExportable := buildAdifFields(logLine, adifParams)
+	if !isExportable {
+		return "", false
+	}
+	var adifLine strings.Builder
+	for _, field := range fields {
+		adifLine.WriteString(adifElement(field.Name, field.Value))
+	}
+	adifLine.WriteString("<EOR>")
+
+	return adifLine.String(), true
+}
+
+// buildAdifFields lists the ADIF fields of a QSO, in the order they are written (whatever the output format).
+// A QSO can't be exported without a valid date and calls (isExportable is false). Other invalid values are skipped.
+func buildAdifFields(logLine LogLine, adifParams AdifParams) (fields AdifRecord, isExportable bool) {
+	if !logLine.isExportable() {
+		return nil, false
+	}
+	addField := func(name, value string) {
+		fields = append(fields, AdifField{Name: name, Value: value})
+	}
+	addField("STATION_CALLSIGN", logLine.MyCall)
+	addField("CALL", logLine.Call)
+	addField("QSO_DATE", adifDate(logLine.Date))
+	addField("TIME_ON", logLine.Time)
+	addField("BAND", logLine.Band)
+	//The FLE mode can be an ADIF submode (FT4 is a submode of MFSK)
+	if mode, isMode := fleModes[logLine.Mode]; isMode {
+		addField("MODE", mode.AdifMode)
+		if mode.AdifSubmode != "" {
+			addField("SUBMODE", mode.AdifSubmode)
 		}
-		if isWWFF {
-			adifLine.WriteString(adifElement("MY_SIG", "WWFF"))
-			adifLine.WriteString(adifElement("MY_SIG_INFO", logLine.MyWWFF))
-			if logLine.WWFF != "" {
-				adifLine.WriteString(adifElement("SIG", "WWFF"))
-				adifLine.WriteString(adifElement("SIG_INFO", logLine.WWFF))
+	} else {
+		addField("MODE", logLine.Mode)
+	}
+	if logLine.Frequency != "" {
+		addField("FREQ", logLine.Frequency)
+	}
+	if logLine.IsValid(FieldRSTsent) {
+		addField("RST_SENT", logLine.RSTsent)
+	}
+	if logLine.IsValid(FieldRSTrcvd) {
+		addField("RST_RCVD", logLine.RSTrcvd)
+	}
+	if logLine.STX != "" {
+		addField("STX", logLine.STX)
+	}
+	if logLine.SRX != "" {
+		addField("SRX", logLine.SRX)
+	}
+	if logLine.STXstring != "" {
+		addField("STX_STRING", logLine.STXstring)
+	}
+	if logLine.SRXstring != "" {
+		addField("SRX_STRING", logLine.SRXstring)
+	}
+	if logLine.Comment != "" {
+		addField("COMMENT", logLine.Comment)
+	}
+	if logLine.OMname != "" {
+		addField("NAME", logLine.OMname)
+	}
+	if logLine.GridLoc != "" && logLine.IsValid(FieldGridLoc) {
+		addField("GRIDSQUARE", logLine.GridLoc)
+	}
+	if logLine.QSLmsg != "" {
+		addField("QSLMSG", logLine.QSLmsg)
+	}
+	//Before ADIF 3.1.4, the WWFF and POTA references are passed as special interest activity (SIG)
+	isRefFields := isRefFieldsAdifVersion(adifParams)
+	if adifParams.IsWWFFcli && logLine.IsValid(FieldMyWWFF) {
+		if isRefFields {
+			//MY_WWFF_REF holds a single reference. Several references are only left in the POTA files
+			//of a WWFF and POTA n-fer split: they are not written there.
+			if len(splitRefList(logLine.MyWWFF)) == 1 {
+				addField("MY_WWFF_REF", logLine.MyWWFF)
 			}
-		}
-		if isSOTA {
-			adifLine.WriteString(adifElement("MY_SOTA_REF", logLine.MySOTA))
-			if logLine.SOTA != "" {
-				adifLine.WriteString(adifElement("SOTA_REF", logLine.SOTA))
+			if logLine.WWFF != "" {
+				addField("WWFF_REF", logLine.WWFF)
 			}
+		} else {
+			fields = append(fields, adifSigFields("WWFF", logLine.MyWWFF, logLine.WWFF)...)
 		}
-		if logLine.Operator != "" {
-			adifLine.WriteString(adifElement("OPERATOR", logLine.Operator))
-		}
-		if logLine.MyGrid != "" {
-			adifLine.WriteString(adifElement("MY_GRIDSQUARE", logLine.MyGrid))
+	}
+	if adifParams.IsSOTAcli && logLine.IsValid(FieldMySOTA) {
+		addField("MY_SOTA_REF", logLine.MySOTA)
+		if logLine.SOTA != "" {
+			addField("SOTA_REF", logLine.SOTA)
 		}
-		if logLine.Nickname != "" {
-			adifLine.WriteString(adifElement("APP_EQSL_QTH_NICKNAME", logLine.Nickname))
+	}
+	if adifParams.IsPOTAcli && logLine.IsValid(FieldMyPOTA) {
+		if isRefFields {
+			addField("MY_POTA_REF", logLine.MyPOTA)
+			if logLine.POTA != "" {
+				addField("POTA_REF", logLine.POTA)
+			}
+		} else {
+			fields = append(fields, adifSigFields("POTA", logLine.MyPOTA, logLine.POTA)...)
 		}
-		adifLine.WriteString("<EOR>")
+	}
+	if logLine.Operator != "" && logLine.IsValid(FieldOperator) {
+		addField("OPERATOR", logLine.Operator)
+	}
+	if logLine.MyGrid != "" && logLine.IsValid(FieldMyGrid) {
+		addField("MY_GRIDSQUARE", logLine.MyGrid)
+	}
+	if logLine.Nickname != "" {
+		addField("APP_EQSL_QTH_NICKNAME", logLine.Nickname)
+	}
 
-		adifList = append(adifList, adifLine.String())
+	return fields, true
+}
 
+// AdifWriter writes an ADIF file one QSO at a time. The header is written with the first QSO.
+type AdifWriter struct {
+	writer          *bufio.Writer
+	adifParams      AdifParams
+	isHeaderWritten bool
+}
+
+// NewAdifWriter creates an ADIF writer sending its output to the supplied writer
+func NewAdifWriter(writer io.Writer, adifParams AdifParams) *AdifWriter {
+	return &AdifWriter{writer: bufio.NewWriter(writer), adifParams: adifParams}
+}
+
+// Write converts the QSO to an ADIF record and writes it. QSOs that can't be exported are skipped.
+func (adifWriter *AdifWriter) Write(logLine LogLine) error {
+	if err := adifWriter.writeHeader(); err != nil {
+		return err
+	}
+	adifRecord, isExportable := buildAdifRecord(logLine, adifWriter.adifParams)
+	if !isExportable {
+		return nil
 	}
+	_, err := adifWriter.writer.WriteString(adifRecord + "\n")
+	return err
+}
 
-	return adifList
+// Flush writes the buffered data (and the header if no QSO was written)
+func (adifWriter *AdifWriter) Flush() error {
+	if err := adifWriter.writeHeader(); err != nil {
+		return err
+	}
+	return adifWriter.writer.Flush()
+}
+
+// writeHeader writes the ADIF header if not done yet
+func (adifWriter *AdifWriter) writeHeader() error {
+	if adifWriter.isHeaderWritten {
+		return nil
+	}
+	adifWriter.isHeaderWritten = true
+	for _, headerLine := range adifHeader(adifWriter.adifParams) {
+		if _, err := adifWriter.writer.WriteString(headerLine + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adifSigFields generates the special interest activity (SIG) fields of the activated and worked references
+func adifSigFields(sig, myReference, reference string) (fields AdifRecord) {
+	fields = append(fields, AdifField{Name: "MY_SIG", Value: sig}, AdifField{Name: "MY_SIG_INFO", Value: myReference})
+	if reference != "" {
+		fields = append(fields, AdifField{Name: "SIG", Value: sig}, AdifField{Name: "SIG_INFO", Value: reference})
+	}
+	return fields
 }
 
 // adifElement generated the ADIF sub-element
@@ -17,7 +17,9 @@ limitations under the License.
 */
 
 import (
+	"bytes"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -94,10 +96,69 @@ func Test_buildAdif(t *testing.T) {
 		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>ON4LY <QSO_DATE:8>20200524 <TIME_ON:4>1312 <BAND:3>20m <MODE:2>CW <RST_SENT:3>559 <RST_RCVD:3>599 <MY_SIG:4>WWFF <MY_SIG_INFO:9>ONFF-0259 <SIG:4>WWFF <SIG_INFO:9>DLFF-0001 <OPERATOR:6>ON4KJM <MY_GRIDSQUARE:6>JO40eu <EOR>",
 	}
 
+	sampleFilledLog4 := []LogLine{
+		{MyCall: "WB9ZZZ", Call: "WY7FD", Date: "2016-01-02", Time: "1839", Band: "15m", Mode: "RTTY", RSTsent: "599", RSTrcvd: "599", STX: "33", SRXstring: "WY"},
+		{MyCall: "WB9ZZZ", Call: "OK1DOL", Date: "2015-12-18", Time: "1924", Band: "160m", Mode: "CW", RSTsent: "599", RSTrcvd: "599", SRX: "12", STXstring: "JN58", SRXstring: "JN69"},
+	}
+
+	expectedOutput4 := []string{
+		"ADIF Export for Fast Log Entry by DF3CB",
+		"<PROGRAMID:3>FLE",
+		"<ADIF_VER:5>3.1.0",
+		"<EOH>",
+		"<STATION_CALLSIGN:6>WB9ZZZ <CALL:5>WY7FD <QSO_DATE:8>20160102 <TIME_ON:4>1839 <BAND:3>15m <MODE:4>RTTY <RST_SENT:3>599 <RST_RCVD:3>599 <STX:2>33 <SRX_STRING:2>WY <EOR>",
+		"<STATION_CALLSIGN:6>WB9ZZZ <CALL:6>OK1DOL <QSO_DATE:8>20151218 <TIME_ON:4>1924 <BAND:4>160m <MODE:2>CW <RST_SENT:3>599 <RST_RCVD:3>599 <SRX:2>12 <STX_STRING:4>JN58 <SRX_STRING:4>JN69 <EOR>",
+	}
+
+	sampleFilledLog5 := []LogLine{
+		{MyCall: "ON4KJM/P", Call: "S57LC", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW", RSTsent: "599", RSTrcvd: "599", MyPOTA: "ON-00259"},
+		{MyCall: "ON4KJM/P", Call: "ON4LY", Date: "2020-05-24", Time: "1312", Band: "20m", Mode: "CW", RSTsent: "559", RSTrcvd: "599", MyPOTA: "ON-00259", POTA: "K-1234"},
+	}
+
+	expectedOutput5 := []string{
+		"ADIF Export for Fast Log Entry by DF3CB",
+		"<PROGRAMID:3>FLE",
+		"<ADIF_VER:5>3.1.4",
+		"<EOH>",
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>S57LC <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:2>CW <RST_SENT:3>599 <RST_RCVD:3>599 <MY_POTA_REF:8>ON-00259 <EOR>",
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>ON4LY <QSO_DATE:8>20200524 <TIME_ON:4>1312 <BAND:3>20m <MODE:2>CW <RST_SENT:3>559 <RST_RCVD:3>599 <MY_POTA_REF:8>ON-00259 <POTA_REF:6>K-1234 <EOR>",
+	}
+
+	expectedOutput6 := []string{
+		"ADIF Export for Fast Log Entry by DF3CB",
+		"<PROGRAMID:3>FLE",
+		"<ADIF_VER:5>3.1.0",
+		"<EOH>",
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>S57LC <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:2>CW <RST_SENT:3>599 <RST_RCVD:3>599 <MY_SIG:4>POTA <MY_SIG_INFO:8>ON-00259 <EOR>",
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>ON4LY <QSO_DATE:8>20200524 <TIME_ON:4>1312 <BAND:3>20m <MODE:2>CW <RST_SENT:3>559 <RST_RCVD:3>599 <MY_SIG:4>POTA <MY_SIG_INFO:8>ON-00259 <SIG:4>POTA <SIG_INFO:6>K-1234 <EOR>",
+	}
+
+	expectedOutput7 := []string{
+		"ADIF Export for Fast Log Entry by DF3CB",
+		"<PROGRAMID:3>FLE",
+		"<ADIF_VER:5>3.1.4",
+		"<EOH>",
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>S57LC <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:2>CW <FREQ:6>14.045 <RST_SENT:3>599 <RST_RCVD:3>599 <GRIDSQUARE:4>JO50 <MY_WWFF_REF:9>ONFF-0259 <OPERATOR:6>ON4KJM <MY_GRIDSQUARE:6>JO40eu <APP_EQSL_QTH_NICKNAME:11>ONFF-0259-1 <EOR>",
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>ON4LY <QSO_DATE:8>20200524 <TIME_ON:4>1312 <BAND:3>20m <MODE:2>CW <RST_SENT:3>559 <RST_RCVD:3>599 <MY_WWFF_REF:9>ONFF-0259 <WWFF_REF:9>DLFF-0001 <OPERATOR:6>ON4KJM <MY_GRIDSQUARE:6>JO40eu <EOR>",
+	}
+
+	//Invalid values (flagged while loading the log)
+	invalidLog := []LogLine{
+		{MyCall: "ON4KJM/P", Call: "FOOBAR", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW", RSTsent: "599", RSTrcvd: "599", InvalidFields: FieldSet(0).with(FieldCall, true)},
+		{MyCall: "ON4KJM/P", Call: "ON4LY", Date: "2020-05-24", Time: "1312", Band: "20m", Mode: "FM", RSTsent: "59", RSTrcvd: "599", GridLoc: "grid", InvalidFields: FieldSet(0).with(FieldRSTrcvd, true).with(FieldGridLoc, true)},
+	}
+
+	expectedInvalidOutput := []string{
+		"ADIF Export for Fast Log Entry by DF3CB",
+		"<PROGRAMID:3>FLE",
+		"<ADIF_VER:5>3.1.0",
+		"<EOH>",
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>ON4LY <QSO_DATE:8>20200524 <TIME_ON:4>1312 <BAND:3>20m <MODE:2>FM <RST_SENT:2>59 <EOR>",
+	}
+
 	type args struct {
-		fullLog []LogLine
-		isWWFF  bool
-		isSOTA  bool
+		fullLog    []LogLine
+		adifParams AdifParams
 	}
 	tests := []struct {
 		name         string
@@ -106,29 +167,100 @@ func Test_buildAdif(t *testing.T) {
 	}{
 		{
 			"Happy case-WWFF",
-			args{fullLog: sampleFilledLog1, isWWFF: true, isSOTA: false},
+			args{fullLog: sampleFilledLog1, adifParams: AdifParams{IsWWFFcli: true}},
 			expectedOutput1,
 		},
 		{
 			"Happy case-Grid",
-			args{fullLog: sampleFilledLog2, isWWFF: true, isSOTA: false},
+			args{fullLog: sampleFilledLog2, adifParams: AdifParams{IsWWFFcli: true}},
 			expectedOutput2,
 		},
 		{
 			"Happy case-Park2Park",
-			args{fullLog: sampleFilledLog3, isWWFF: true, isSOTA: false},
+			args{fullLog: sampleFilledLog3, adifParams: AdifParams{IsWWFFcli: true}},
 			expectedOutput3,
 		},
+		{
+			"Happy case-Contest",
+			args{fullLog: sampleFilledLog4, adifParams: AdifParams{}},
+			expectedOutput4,
+		},
+		{
+			"Happy case-POTA",
+			args{fullLog: sampleFilledLog5, adifParams: AdifParams{IsPOTAcli: true, AdifVersion: "3.1.4"}},
+			expectedOutput5,
+		},
+		{
+			"Happy case-POTA (ADIF 3.1.0)",
+			args{fullLog: sampleFilledLog5, adifParams: AdifParams{IsPOTAcli: true, AdifVersion: "3.1.0"}},
+			expectedOutput6,
+		},
+		{
+			"Happy case-Park2Park (ADIF 3.1.4)",
+			args{fullLog: sampleFilledLog3, adifParams: AdifParams{IsWWFFcli: true, AdifVersion: "3.1.4"}},
+			expectedOutput7,
+		},
+		{
+			"Invalid values are skipped",
+			args{fullLog: invalidLog, adifParams: AdifParams{}},
+			expectedInvalidOutput,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotAdifList := buildAdif(tt.args.fullLog, tt.args.isWWFF, tt.args.isSOTA); !reflect.DeepEqual(gotAdifList, tt.wantAdifList) {
+			if gotAdifList := buildAdif(tt.args.fullLog, tt.args.adifParams); !reflect.DeepEqual(gotAdifList, tt.wantAdifList) {
 				t.Errorf("buildAdif() = %v, want %v", gotAdifList, tt.wantAdifList)
 			}
 		})
 	}
 }
 
+func TestAdifWriter(t *testing.T) {
+	fullLog := []LogLine{
+		{MyCall: "ON4KJM/P", Call: "S57LC", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW", RSTsent: "599", RSTrcvd: "599"},
+		{MyCall: "ON4KJM/P", Call: "FOOBAR", Date: "2020-05-24", Time: "1311", Band: "20m", Mode: "CW", InvalidFields: FieldSet(0).with(FieldCall, true)},
+		{MyCall: "ON4KJM/P", Call: "ON4LY", Date: "2020-05-24", Time: "1312", Band: "20m", Mode: "CW", RSTsent: "559", RSTrcvd: "599"},
+	}
+	tests := []struct {
+		name       string
+		fullLog    []LogLine
+		wantOutput string
+	}{
+		{
+			"Records are written as they come",
+			fullLog,
+			"ADIF Export for Fast Log Entry by DF3CB\n" +
+				"<PROGRAMID:3>FLE\n" +
+				"<ADIF_VER:5>3.1.0\n" +
+				"<EOH>\n" +
+				"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>S57LC <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:2>CW <RST_SENT:3>599 <RST_RCVD:3>599 <EOR>\n" +
+				"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>ON4LY <QSO_DATE:8>20200524 <TIME_ON:4>1312 <BAND:3>20m <MODE:2>CW <RST_SENT:3>559 <RST_RCVD:3>599 <EOR>\n",
+		},
+		{
+			"Header only",
+			nil,
+			"ADIF Export for Fast Log Entry by DF3CB\n<PROGRAMID:3>FLE\n<ADIF_VER:5>3.1.0\n<EOH>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			adifWriter := NewAdifWriter(&output, AdifParams{})
+			for _, logLine := range tt.fullLog {
+				if err := adifWriter.Write(logLine); err != nil {
+					t.Fatalf("AdifWriter.Write() unexpected error: %v", err)
+				}
+			}
+			if err := adifWriter.Flush(); err != nil {
+				t.Fatalf("AdifWriter.Flush() unexpected error: %v", err)
+			}
+			if gotOutput := output.String(); gotOutput != tt.wantOutput {
+				t.Errorf("AdifWriter output = %v, want %v", gotOutput, tt.wantOutput)
+			}
+		})
+	}
+}
+
 func Test_adifDate(t *testing.T) {
 	type args struct {
 		inputDate string
@@ -158,3 +290,39 @@ func Test_adifDate(t *testing.T) {
 		})
 	}
 }
+
+//The dB reports of the digital modes are exported as entered (with two digits)
+func Test_buildAdif_digitalReports(t *testing.T) {
+	fullLog, diagnostics := LoadLog(strings.NewReader("mycall ON4KJM/P\ndate 2020-05-24 20m ft8\n1310 dl1aa -15 +3\n1312 dl2bb\n"), false)
+	if len(diagnostics) != 0 {
+		t.Fatalf("LoadLog() unexpected diagnostics: %v", diagnostics)
+	}
+	wantRecords := []string{
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>DL1AA <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:3>FT8 <RST_SENT:3>-15 <RST_RCVD:3>+03 <EOR>",
+		"<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>DL2BB <QSO_DATE:8>20200524 <TIME_ON:4>1312 <BAND:3>20m <MODE:3>FT8 <RST_SENT:3>-10 <RST_RCVD:3>-10 <EOR>",
+	}
+	if gotRecords := buildAdif(fullLog, AdifParams{})[4:]; !reflect.DeepEqual(gotRecords, wantRecords) {
+		t.Errorf("buildAdif() = %v, want %v", gotRecords, wantRecords)
+	}
+}
+
+//The FLE modes that are ADIF submodes are exported with their mode
+func Test_buildAdifRecord_submode(t *testing.T) {
+	tests := []struct {
+		mode       string
+		wantRecord string
+	}{
+		{"CW", "<STATION_CALLSIGN:6>ON4KJM <CALL:5>DL1AA <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:2>CW <RST_SENT:2>59 <RST_RCVD:2>59 <EOR>"},
+		{"FT4", "<STATION_CALLSIGN:6>ON4KJM <CALL:5>DL1AA <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:4>MFSK <SUBMODE:3>FT4 <RST_SENT:2>59 <RST_RCVD:2>59 <EOR>"},
+		{"USB", "<STATION_CALLSIGN:6>ON4KJM <CALL:5>DL1AA <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:3>SSB <SUBMODE:3>USB <RST_SENT:2>59 <RST_RCVD:2>59 <EOR>"},
+		{"C4FM", "<STATION_CALLSIGN:6>ON4KJM <CALL:5>DL1AA <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:12>DIGITALVOICE <SUBMODE:4>C4FM <RST_SENT:2>59 <RST_RCVD:2>59 <EOR>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			logLine := LogLine{MyCall: "ON4KJM", Call: "DL1AA", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: tt.mode, RSTsent: "59", RSTrcvd: "59"}
+			if gotRecord, _ := buildAdifRecord(logLine, AdifParams{}); gotRecord != tt.wantRecord {
+				t.Errorf("buildAdifRecord() = %v, want %v", gotRecord, tt.wantRecord)
+			}
+		})
+	}
+}
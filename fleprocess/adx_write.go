@@ -0,0 +1,142 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//Documentation of the ADX format: https://adif.org/314/ADIF_314.htm#ADX_File_Format
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// adxFooter closes the records and the document
+var adxFooter = []string{"  </RECORDS>", "</ADX>"}
+
+// OutputAdx generates and writes data in ADX (XML ADIF) format
+func OutputAdx(outputFile string, fullLog []LogLine, adifParams AdifParams) {
+
+	//convert the log data to an in-memory ADX file
+	adxData := buildAdx(fullLog, adifParams)
+
+	//write to a file
+	writeFile(outputFile, adxData)
+}
+
+// buildAdx creates the ADX file in memory ready to be printed. The records are the same as in the ADIF file.
+func buildAdx(fullLog []LogLine, adifParams AdifParams) (adxList []string) {
+	adxList = adxHeader(adifParams)
+	for _, logLine := range fullLog {
+		if adxRecord, isExportable := buildAdxRecord(logLine, adifParams); isExportable {
+			adxList = append(adxList, adxRecord...)
+		}
+	}
+	return append(adxList, adxFooter...)
+}
+
+// adxHeader returns the lines of the XML declaration, the ADX header and the start of the records
+func adxHeader(adifParams AdifParams) []string {
+	headerLines := []string{`<?xml version="1.0" encoding="UTF-8"?>`, "<ADX>", "  <HEADER>", "    <!--" + adifPreamble + "-->"}
+	for _, field := range adifHeaderValues(adifParams) {
+		headerLines = append(headerLines, "    "+adxElement(field))
+	}
+	return append(headerLines, "  </HEADER>", "  <RECORDS>")
+}
+
+// buildAdxRecord converts a QSO to the lines of an ADX record.
+// A QSO can't be exported without a valid date and calls (isExportable is false).
+func buildAdxRecord(logLine LogLine, adifParams AdifParams) (adxRecord []string, isExportable bool) {
+	fields, isExportable := buildAdifFields(logLine, adifParams)
+	if !isExportable {
+		return nil, false
+	}
+	adxRecord = append(adxRecord, "    <RECORD>")
+	for _, field := range fields {
+		adxRecord = append(adxRecord, "      "+adxElement(field))
+	}
+	return append(adxRecord, "    </RECORD>"), true
+}
+
+// adxElement generates the XML element of a field.
+// Application defined fields (APP_PROGRAMID_FIELDNAME) are written as APP elements.
+func adxElement(field AdifField) string {
+	var value strings.Builder
+	xml.EscapeText(&value, []byte(field.Value))
+
+	if strings.HasPrefix(field.Name, "APP_") {
+		if appField := strings.SplitN(strings.TrimPrefix(field.Name, "APP_"), "_", 2); len(appField) == 2 {
+			return fmt.Sprintf("<APP PROGRAMID=\"%s\" FIELDNAME=\"%s\" TYPE=\"S\">%s</APP>", appField[0], appField[1], value.String())
+		}
+	}
+	return fmt.Sprintf("<%s>%s</%s>", field.Name, value.String(), field.Name)
+}
+
+// AdxWriter writes an ADX file one QSO at a time. The header is written with the first QSO, the end of the document when flushing.
+type AdxWriter struct {
+	writer          *bufio.Writer
+	adifParams      AdifParams
+	isHeaderWritten bool
+}
+
+// NewAdxWriter creates an ADX writer sending its output to the supplied writer
+func NewAdxWriter(writer io.Writer, adifParams AdifParams) *AdxWriter {
+	return &AdxWriter{writer: bufio.NewWriter(writer), adifParams: adifParams}
+}
+
+// Write converts the QSO to an ADX record and writes it. QSOs that can't be exported are skipped.
+func (adxWriter *AdxWriter) Write(logLine LogLine) error {
+	if err := adxWriter.writeHeader(); err != nil {
+		return err
+	}
+	adxRecord, isExportable := buildAdxRecord(logLine, adxWriter.adifParams)
+	if !isExportable {
+		return nil
+	}
+	return adxWriter.writeLines(adxRecord)
+}
+
+// Flush closes the document and writes the buffered data. It must be called once, after the last QSO.
+func (adxWriter *AdxWriter) Flush() error {
+	if err := adxWriter.writeHeader(); err != nil {
+		return err
+	}
+	if err := adxWriter.writeLines(adxFooter); err != nil {
+		return err
+	}
+	return adxWriter.writer.Flush()
+}
+
+// writeHeader writes the ADX header if not done yet
+func (adxWriter *AdxWriter) writeHeader() error {
+	if adxWriter.isHeaderWritten {
+		return nil
+	}
+	adxWriter.isHeaderWritten = true
+	return adxWriter.writeLines(adxHeader(adxWriter.adifParams))
+}
+
+// writeLines writes the lines to the buffered output
+func (adxWriter *AdxWriter) writeLines(lines []string) error {
+	for _, line := range lines {
+		if _, err := adxWriter.writer.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
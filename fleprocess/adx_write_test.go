@@ -0,0 +1,162 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_buildAdx(t *testing.T) {
+	fullLog := []LogLine{
+		{MyCall: "ON4KJM/P", Call: "S57LC", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW", RSTsent: "599", RSTrcvd: "599",
+			MyWWFF: "ONFF-0258", Operator: "ON4KJM", Comment: "QRP <5W> & wire", Nickname: "Grimbergen"},
+		{MyCall: "ON4KJM/P", Call: "FOOBAR", Date: "2020-05-24", Time: "1311", Band: "20m", Mode: "CW", InvalidFields: FieldSet(0).with(FieldCall, true)},
+	}
+	wantAdx := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		"<ADX>",
+		"  <HEADER>",
+		"    <!--ADIF Export for Fast Log Entry by DF3CB-->",
+		"    <PROGRAMID>FLE</PROGRAMID>",
+		"    <ADIF_VER>3.1.4</ADIF_VER>",
+		"  </HEADER>",
+		"  <RECORDS>",
+		"    <RECORD>",
+		"      <STATION_CALLSIGN>ON4KJM/P</STATION_CALLSIGN>",
+		"      <CALL>S57LC</CALL>",
+		"      <QSO_DATE>20200524</QSO_DATE>",
+		"      <TIME_ON>1310</TIME_ON>",
+		"      <BAND>20m</BAND>",
+		"      <MODE>CW</MODE>",
+		"      <RST_SENT>599</RST_SENT>",
+		"      <RST_RCVD>599</RST_RCVD>",
+		"      <COMMENT>QRP &lt;5W&gt; &amp; wire</COMMENT>",
+		"      <MY_WWFF_REF>ONFF-0258</MY_WWFF_REF>",
+		"      <OPERATOR>ON4KJM</OPERATOR>",
+		`      <APP PROGRAMID="EQSL" FIELDNAME="QTH_NICKNAME" TYPE="S">Grimbergen</APP>`,
+		"    </RECORD>",
+		"  </RECORDS>",
+		"</ADX>",
+	}
+
+	if gotAdx := buildAdx(fullLog, AdifParams{IsWWFFcli: true, AdifVersion: "3.1.4"}); !reflect.DeepEqual(gotAdx, wantAdx) {
+		t.Errorf("buildAdx() = \n%v, want \n%v", strings.Join(gotAdx, "\n"), strings.Join(wantAdx, "\n"))
+	}
+}
+
+//adxDocument is used to decode the generated ADX files
+type adxDocument struct {
+	Header struct {
+		Fields []adxField `xml:",any"`
+	} `xml:"HEADER"`
+	Records []struct {
+		Fields []adxField `xml:",any"`
+	} `xml:"RECORDS>RECORD"`
+}
+
+type adxField struct {
+	XMLName   xml.Name
+	ProgramID string `xml:"PROGRAMID,attr"`
+	FieldName string `xml:"FIELDNAME,attr"`
+	Value     string `xml:",chardata"`
+}
+
+//The ADX file holds the same records as the ADIF file
+func Test_buildAdx_sameRecordsAsAdif(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		adifParams AdifParams
+	}{
+		{"WWFF and SOTA", "../test/data/sample_wwff_sota.txt", AdifParams{IsWWFFcli: true, IsSOTAcli: true}},
+		{"WWFF with ADIF 3.1.4", "../test/data/ON4KJM@ONFF-025920200524.txt", AdifParams{IsWWFFcli: true, AdifVersion: "3.1.4"}},
+		{"Contest", "../test/data/sample_contest_ru.txt", AdifParams{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, _ := ioutil.ReadFile(tt.filename)
+			loadedLog, _ := LoadLog(bytes.NewReader(input), true)
+
+			adifFile, err := ReadAdif(strings.NewReader(strings.Join(buildAdif(loadedLog, tt.adifParams), "\n")))
+			if err != nil {
+				t.Fatalf("ReadAdif() unexpected error: %v", err)
+			}
+			var document adxDocument
+			if err := xml.Unmarshal([]byte(strings.Join(buildAdx(loadedLog, tt.adifParams), "\n")), &document); err != nil {
+				t.Fatalf("xml.Unmarshal() unexpected error: %v", err)
+			}
+
+			if len(document.Records) != len(adifFile.Records) {
+				t.Fatalf("ADX holds %d records, want %d", len(document.Records), len(adifFile.Records))
+			}
+			for i, record := range adifFile.Records {
+				var adxRecord AdifRecord
+				for _, field := range document.Records[i].Fields {
+					name := field.XMLName.Local
+					if name == "APP" {
+						name = "APP_" + field.ProgramID + "_" + field.FieldName
+					}
+					adxRecord = append(adxRecord, AdifField{Name: name, Value: field.Value})
+				}
+				var adifRecord AdifRecord
+				for _, field := range record {
+					adifRecord = append(adifRecord, AdifField{Name: field.Name, Value: field.Value})
+				}
+				if !reflect.DeepEqual(adxRecord, adifRecord) {
+					t.Errorf("ADX record #%d = %v, want %v", i+1, adxRecord, adifRecord)
+				}
+			}
+		})
+	}
+}
+
+func TestAdxWriter(t *testing.T) {
+	fullLog := []LogLine{
+		{MyCall: "ON4KJM/P", Call: "S57LC", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW"},
+		{MyCall: "ON4KJM/P", Call: "FOOBAR", Date: "2020-05-24", Time: "1311", Band: "20m", Mode: "CW", InvalidFields: FieldSet(0).with(FieldCall, true)},
+	}
+	tests := []struct {
+		name    string
+		fullLog []LogLine
+	}{
+		{"Records are written as they come", fullLog},
+		{"No record", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			adxWriter := NewAdxWriter(&output, AdifParams{})
+			for _, logLine := range tt.fullLog {
+				if err := adxWriter.Write(logLine); err != nil {
+					t.Fatalf("AdxWriter.Write() unexpected error: %v", err)
+				}
+			}
+			if err := adxWriter.Flush(); err != nil {
+				t.Fatalf("AdxWriter.Flush() unexpected error: %v", err)
+			}
+			wantOutput := strings.Join(buildAdx(tt.fullLog, AdifParams{}), "\n") + "\n"
+			if gotOutput := output.String(); gotOutput != wantOutput {
+				t.Errorf("AdxWriter output = %v, want %v", gotOutput, wantOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//Statement is a line of a FLE log as produced by the parser.
+//It is either a CommentStatement, a HeaderStatement, a ContextStatement or a QsoStatement.
+type Statement interface {
+	//LineNumber is the position of the statement in the input (starting at 1)
+	LineNumber() int
+}
+
+//CommentStatement is a comment line, a line of a multi-line comment or an empty line
+type CommentStatement struct {
+	Line int
+	//Text is the line as found in the input
+	Text string
+}
+
+//HeaderStatement defines a header value ("mycall on4kjm/p", "mywwff onff-0258", ...)
+type HeaderStatement struct {
+	Line int
+	//Keyword is the header keyword in lowercase ("mycall", "mywwff", ...)
+	Keyword string
+	//Value is the text following the keyword, as found in the input
+	Value Token
+}
+
+//DataLine holds the elements of a line of the log data block.
+//The elements are in the order of the input, their type is resolved by the parser.
+type DataLine struct {
+	Line     int
+	Elements []Token
+}
+
+//ContextStatement is a data line without a call. It only sets the context
+//(band, mode, date, frequency or time) of the following QSOs.
+type ContextStatement struct {
+	DataLine
+}
+
+//QsoStatement is a data line describing a QSO
+type QsoStatement struct {
+	DataLine
+}
+
+//LineNumber is the position of the statement in the input
+func (statement CommentStatement) LineNumber() int {
+	return statement.Line
+}
+
+//LineNumber is the position of the statement in the input
+func (statement HeaderStatement) LineNumber() int {
+	return statement.Line
+}
+
+//LineNumber is the position of the statement in the input
+func (dataLine DataLine) LineNumber() int {
+	return dataLine.Line
+}
+
+//Call returns the (last) call of the QSO line
+func (statement QsoStatement) Call() (call Token) {
+	for _, element := range statement.Elements {
+		if element.Type == TokenCall {
+			call = element
+		}
+	}
+	return call
+}
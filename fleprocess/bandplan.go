@@ -0,0 +1,187 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//Documentation of the ADIF bands: https://adif.org/314/ADIF_314.htm#Band_Enumeration
+//The IARU band plans: https://www.iaru.org/on-the-air/band-plans/
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Band holds the limits (in MHz) of an amateur band and the band name used in the SOTA CSV file
+type Band struct {
+	Name       string
+	LowerLimit float64
+	UpperLimit float64
+	SotaName   string
+}
+
+//adifBands lists the bands of the ADIF specification. This band plan is used when no IARU region is selected.
+var adifBands = []Band{
+	{"2190m", 0.1357, 0.1378, "136kHz"},
+	{"630m", 0.472, 0.479, "475kHz"},
+	{"560m", 0.501, 0.504, "500kHz"},
+	{"160m", 1.8, 2.0, "1.8MHz"},
+	{"80m", 3.5, 4.0, "3.5MHz"},
+	{"60m", 5.06, 5.45, "5MHz"},
+	{"40m", 7.0, 7.3, "7MHz"},
+	{"30m", 10.1, 10.15, "10MHz"},
+	{"20m", 14.0, 14.35, "14MHz"},
+	{"17m", 18.068, 18.168, "18MHz"},
+	{"15m", 21.0, 21.45, "21MHz"},
+	{"12m", 24.890, 24.99, "24MHz"},
+	{"10m", 28.0, 29.7, "28MHz"},
+	{"6m", 50, 54, "50MHz"},
+	{"4m", 70, 71, "70MHz"},
+	{"2m", 144, 148, "144MHz"},
+	{"1.25m", 222, 225, "222MHz"},
+	{"70cm", 420, 450, "432MHz"},
+	{"33cm", 902, 928, "900MHz"},
+	{"23cm", 1240, 1300, "1.2GHz"},
+	{"13cm", 2300, 2450, "2.3GHz"},
+	{"9cm", 3300, 3500, "3.4GHz"},
+	{"6cm", 5650, 5925, "5.7GHz"},
+	{"3cm", 10000, 10500, "10GHz"},
+	{"1.25cm", 24000, 24250, "24GHz"},
+	{"6mm", 47000, 47200, "47GHz"},
+	{"4mm", 75500, 81000, "76GHz"},
+	{"2.5mm", 119980, 120020, "120GHz"},
+	{"2mm", 142000, 149000, "144GHz"},
+	{"1mm", 241000, 250000, "241GHz"},
+}
+
+//bandLimits holds the lower and upper limits of a band (in MHz)
+type bandLimits struct {
+	lowerLimit float64
+	upperLimit float64
+}
+
+//regionalLimits lists, per IARU region, the band limits that differ from the ADIF band plan.
+//A band without limits (nil) is not allocated in that region.
+var regionalLimits = map[string]map[string]*bandLimits{
+	"1": {
+		"160m":  {1.81, 2.0},
+		"80m":   {3.5, 3.8},
+		"60m":   {5.3515, 5.3665},
+		"40m":   {7.0, 7.2},
+		"6m":    {50, 52},
+		"4m":    {70, 70.5},
+		"2m":    {144, 146},
+		"1.25m": nil,
+		"70cm":  {430, 440},
+		"33cm":  nil,
+	},
+	"2": {
+		"60m": {5.3305, 5.4065},
+		"4m":  nil,
+	},
+	"3": {
+		"80m":   {3.5, 3.9},
+		"60m":   {5.3515, 5.3665},
+		"40m":   {7.0, 7.2},
+		"4m":    nil,
+		"1.25m": nil,
+		"70cm":  {430, 440},
+		"33cm":  nil,
+	},
+}
+
+//bandPlans holds the bands per IARU region. The ADIF band plan is registered as region "".
+var bandPlans = buildBandPlans()
+
+//defaultRegion is the IARU region used when the log doesn't define one
+var defaultRegion = ""
+
+//buildBandPlans applies the regional limits to the ADIF band plan
+func buildBandPlans() map[string]map[string]Band {
+	plans := map[string]map[string]Band{"": {}}
+	for _, band := range adifBands {
+		plans[""][band.Name] = band
+	}
+	for region, limits := range regionalLimits {
+		plans[region] = map[string]Band{}
+		for _, band := range adifBands {
+			if regionalBand, isRegional := limits[band.Name]; isRegional {
+				if regionalBand == nil {
+					continue
+				}
+				band.LowerLimit = regionalBand.lowerLimit
+				band.UpperLimit = regionalBand.upperLimit
+			}
+			plans[region][band.Name] = band
+		}
+	}
+	return plans
+}
+
+//lookupBand returns the band as allocated in the IARU region (the default region if empty).
+//isAllocated is false if the band is unknown or not allocated in that region.
+func lookupBand(name, region string) (band Band, isAllocated bool) {
+	band, isAllocated = bandPlans[effectiveRegion(region)][strings.ToLower(name)]
+	return band, isAllocated
+}
+
+//effectiveRegion returns the IARU region of the log, or the default region if the log doesn't define one
+func effectiveRegion(region string) string {
+	if region == "" {
+		return defaultRegion
+	}
+	return region
+}
+
+//lookupBandOfFrequency returns the band containing the frequency (in MHz), as allocated in the IARU region
+//(the default region if empty). isFound is false if the frequency is outside of the bands.
+func lookupBandOfFrequency(frequency float64, region string) (band Band, isFound bool) {
+	plan := bandPlans[effectiveRegion(region)]
+	for _, adifBand := range adifBands {
+		if band, isFound = plan[adifBand.Name]; isFound && frequency >= band.LowerLimit && frequency <= band.UpperLimit {
+			return band, true
+		}
+	}
+	return Band{}, false
+}
+
+//ValidateRegion checks that the IARU region is 1, 2 or 3
+func ValidateRegion(region string) (string, *Diagnostic) {
+	region = strings.TrimSpace(region)
+	if _, isKnown := regionalLimits[region]; !isKnown || region == "" {
+		return region, newDiagnostic(CodeInvalidRegion, "[%s] is not a valid IARU region (1, 2 or 3)", region)
+	}
+	return region, nil
+}
+
+//DefaultRegion returns the IARU region used by the logs that don't define one (empty for the ADIF band plan)
+func DefaultRegion() string {
+	return defaultRegion
+}
+
+//SetDefaultRegion selects the IARU region used by the logs that don't define one (for example from the configuration file).
+//An empty region selects the ADIF band plan.
+func SetDefaultRegion(region string) error {
+	if region == "" {
+		defaultRegion = ""
+		return nil
+	}
+	validRegion, diagnostic := ValidateRegion(region)
+	if diagnostic != nil {
+		return fmt.Errorf("%s", diagnostic.Message)
+	}
+	defaultRegion = validRegion
+	return nil
+}
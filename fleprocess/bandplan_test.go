@@ -0,0 +1,150 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+)
+
+func Test_lookupBand(t *testing.T) {
+	type args struct {
+		name   string
+		region string
+	}
+	tests := []struct {
+		name            string
+		args            args
+		wantLowerLimit  float64
+		wantUpperLimit  float64
+		wantIsAllocated bool
+	}{
+		{"ADIF band plan", args{"40m", ""}, 7.0, 7.3, true},
+		{"Region 1", args{"40M", "1"}, 7.0, 7.2, true},
+		{"Region 2", args{"40m", "2"}, 7.0, 7.3, true},
+		{"Region 3", args{"80m", "3"}, 3.5, 3.9, true},
+		{"Same limits in all regions", args{"20m", "1"}, 14.0, 14.35, true},
+		{"Not allocated in region 1", args{"1.25m", "1"}, 0, 0, false},
+		{"Not allocated in region 2", args{"4m", "2"}, 0, 0, false},
+		{"Unknown band", args{"11m", ""}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBand, gotIsAllocated := lookupBand(tt.args.name, tt.args.region)
+			if gotIsAllocated != tt.wantIsAllocated {
+				t.Errorf("lookupBand() gotIsAllocated = %v, want %v", gotIsAllocated, tt.wantIsAllocated)
+			}
+			if gotBand.LowerLimit != tt.wantLowerLimit || gotBand.UpperLimit != tt.wantUpperLimit {
+				t.Errorf("lookupBand() got limits = %v-%v, want %v-%v", gotBand.LowerLimit, gotBand.UpperLimit, tt.wantLowerLimit, tt.wantUpperLimit)
+			}
+		})
+	}
+}
+
+func Test_lookupBandOfFrequency(t *testing.T) {
+	type args struct {
+		frequency float64
+		region    string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantBand    string
+		wantIsFound bool
+	}{
+		{"HF band", args{7.018, ""}, "40m", true},
+		{"Lower band edge", args{14.0, ""}, "20m", true},
+		{"Microwave band", args{10368.2, ""}, "3cm", true},
+		{"Within the region band plan", args{7.25, "2"}, "40m", true},
+		{"Outside of the region band plan", args{7.25, "1"}, "", false},
+		{"Outside of the bands", args{14.453, ""}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBand, gotIsFound := lookupBandOfFrequency(tt.args.frequency, tt.args.region)
+			if gotBand.Name != tt.wantBand || gotIsFound != tt.wantIsFound {
+				t.Errorf("lookupBandOfFrequency() = %v, %v, want %v, %v", gotBand.Name, gotIsFound, tt.wantBand, tt.wantIsFound)
+			}
+		})
+	}
+}
+
+func Test_bandPlans(t *testing.T) {
+	for region, plan := range bandPlans {
+		for name, band := range plan {
+			if band.SotaName == "" || band.SotaName == "tbd" {
+				t.Errorf("band %s of region [%s] has no SOTA band name", name, region)
+			}
+			if band.LowerLimit <= 0 || band.LowerLimit >= band.UpperLimit {
+				t.Errorf("band %s of region [%s] has invalid limits: %v-%v", name, region, band.LowerLimit, band.UpperLimit)
+			}
+			if _, isAdifBand := bandPlans[""][name]; !isAdifBand {
+				t.Errorf("band %s of region [%s] is not an ADIF band", name, region)
+			}
+		}
+	}
+	for region, limits := range regionalLimits {
+		for name := range limits {
+			if _, isAdifBand := bandPlans[""][name]; !isAdifBand {
+				t.Errorf("regional limits of region %s: %s is not an ADIF band", region, name)
+			}
+		}
+	}
+}
+
+func TestValidateRegion(t *testing.T) {
+	tests := []struct {
+		name        string
+		region      string
+		wantRegion  string
+		wantMessage string
+	}{
+		{"Valid region", " 2 ", "2", ""},
+		{"Invalid region", "4", "4", "[4] is not a valid IARU region (1, 2 or 3)"},
+		{"ADIF band plan is not a region", "", "", "[] is not a valid IARU region (1, 2 or 3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRegion, gotDiagnostic := ValidateRegion(tt.region)
+			if gotRegion != tt.wantRegion {
+				t.Errorf("ValidateRegion() gotRegion = %v, want %v", gotRegion, tt.wantRegion)
+			}
+			if gotMessage := diagnosticMessage(gotDiagnostic); gotMessage != tt.wantMessage {
+				t.Errorf("ValidateRegion() gotMessage = %v, want %v", gotMessage, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestSetDefaultRegion(t *testing.T) {
+	defer func() { defaultRegion = "" }()
+
+	if err := SetDefaultRegion("x"); err == nil {
+		t.Errorf("SetDefaultRegion() should fail for an invalid region")
+	}
+	if err := SetDefaultRegion("1"); err != nil {
+		t.Fatalf("SetDefaultRegion() unexpected error: %v", err)
+	}
+	if band, _ := lookupBand("2m", ""); band.UpperLimit != 146 {
+		t.Errorf("lookupBand() should use the default region: got upper limit %v", band.UpperLimit)
+	}
+	if band, _ := lookupBand("2m", "2"); band.UpperLimit != 148 {
+		t.Errorf("lookupBand() should use the region of the log: got upper limit %v", band.UpperLimit)
+	}
+	if err := SetDefaultRegion(""); err != nil || DefaultRegion() != "" {
+		t.Errorf("SetDefaultRegion() should select the ADIF band plan: got region [%s], error %v", DefaultRegion(), err)
+	}
+}
@@ -0,0 +1,135 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//Documentation of the Cabrillo 3.0 format: https://wwrof.org/cabrillo/cabrillo-specification-v3/
+
+import (
+	"fmt"
+	"strings"
+)
+
+//CabrilloHeader contains the Cabrillo header values that can't be found in the FLE log
+type CabrilloHeader struct {
+	Contest             string
+	CategoryOperator    string
+	CategoryAssisted    string
+	CategoryBand        string
+	CategoryMode        string
+	CategoryPower       string
+	CategoryStation     string
+	CategoryTransmitter string
+	ClaimedScore        int
+	Operators           string
+	Location            string
+}
+
+//ProcessCabrilloCommand loads an FLE input to produce a Cabrillo contest log. It is called from the COBRA interface
+func ProcessCabrilloCommand(inputFilename, outputFilename string, isInterpolateTime, isOverwrite bool, cabrilloHeader CabrilloHeader) error {
+
+	//Check the values supplied on the command line
+	if err := validateCabrilloHeader(&cabrilloHeader); err != nil {
+		return err
+	}
+
+	//Validate or build the output filename
+	var verifiedOutputFilename string
+	var err error
+
+	if verifiedOutputFilename, err = buildOutputFilename(outputFilename, inputFilename, isOverwrite, ".log"); err != nil {
+		return err
+	}
+
+	//Load the input file
+	var loadedLogFile []LogLine
+	var isLoadedOK bool
+
+	if loadedLogFile, isLoadedOK = LoadFile(inputFilename, isInterpolateTime); isLoadedOK == false {
+		return fmt.Errorf("There were input file parsing errors. Could not generate Cabrillo file")
+	}
+
+	//Check if we have all the necessary data (same requirements as a plain ADIF file)
+	if err := validateDataforAdif(loadedLogFile, AdifParams{}); err != nil {
+		return err
+	}
+
+	//Write the output file with the checked data
+	outputCabrillo(verifiedOutputFilename, loadedLogFile, cabrilloHeader)
+
+	//If we reached this point, everything was processed OK and the file generated
+	return nil
+}
+
+//cabrilloCategoryBands lists the CATEGORY-BAND values of the Cabrillo 3.0 specification
+var cabrilloCategoryBands = []string{"ALL", "160M", "80M", "40M", "20M", "15M", "10M", "6M", "4M", "2M", "222", "432", "902",
+	"1.2G", "2.3G", "3.4G", "5.7G", "10G", "24G", "47G", "75G", "119G", "134G", "241G", "LIGHT", "VHF-3-BAND", "VHF-FM-ONLY"}
+
+//validCabrilloCategories lists the accepted values of the enumerated Cabrillo categories
+var validCabrilloCategories = map[string][]string{
+	"CATEGORY-OPERATOR":    {"SINGLE-OP", "MULTI-OP", "CHECKLOG"},
+	"CATEGORY-ASSISTED":    {"ASSISTED", "NON-ASSISTED"},
+	"CATEGORY-BAND":        cabrilloCategoryBands,
+	"CATEGORY-MODE":        {"CW", "DIGI", "FM", "RTTY", "SSB", "MIXED"},
+	"CATEGORY-POWER":       {"HIGH", "LOW", "QRP"},
+	"CATEGORY-STATION":     {"DISTRIBUTED", "FIXED", "MOBILE", "PORTABLE", "ROVER", "ROVER-LIMITED", "ROVER-UNLIMITED", "EXPEDITION", "HQ", "SCHOOL"},
+	"CATEGORY-TRANSMITTER": {"ONE", "TWO", "LIMITED", "UNLIMITED", "SWL"},
+}
+
+//validateCabrilloHeader checks the mandatory and enumerated Cabrillo header values.
+//The enumerated values are normalized to uppercase.
+func validateCabrilloHeader(cabrilloHeader *CabrilloHeader) error {
+	if strings.TrimSpace(cabrilloHeader.Contest) == "" {
+		return fmt.Errorf("Missing contest name")
+	}
+	if cabrilloHeader.ClaimedScore < 0 {
+		return fmt.Errorf("Invalid claimed score (%d)", cabrilloHeader.ClaimedScore)
+	}
+
+	categories := []struct {
+		tag   string
+		value *string
+	}{
+		{"CATEGORY-OPERATOR", &cabrilloHeader.CategoryOperator},
+		{"CATEGORY-ASSISTED", &cabrilloHeader.CategoryAssisted},
+		{"CATEGORY-BAND", &cabrilloHeader.CategoryBand},
+		{"CATEGORY-MODE", &cabrilloHeader.CategoryMode},
+		{"CATEGORY-POWER", &cabrilloHeader.CategoryPower},
+		{"CATEGORY-STATION", &cabrilloHeader.CategoryStation},
+		{"CATEGORY-TRANSMITTER", &cabrilloHeader.CategoryTransmitter},
+	}
+	for _, category := range categories {
+		*category.value = strings.ToUpper(strings.TrimSpace(*category.value))
+		if *category.value == "" {
+			continue
+		}
+		if !isInList(*category.value, validCabrilloCategories[category.tag]) {
+			return fmt.Errorf("Invalid %s value [%s] (expecting one of %s)", category.tag, *category.value, strings.Join(validCabrilloCategories[category.tag], ", "))
+		}
+	}
+
+	return nil
+}
+
+//isInList returns true if value is one of the elements of the list
+func isInList(value string, list []string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+)
+
+func Test_validateCabrilloHeader(t *testing.T) {
+	tests := []struct {
+		name           string
+		cabrilloHeader CabrilloHeader
+		wantHeader     CabrilloHeader
+		wantErr        bool
+	}{
+		{
+			"Minimal",
+			CabrilloHeader{Contest: "ARRL-RTTY"},
+			CabrilloHeader{Contest: "ARRL-RTTY"},
+			false,
+		},
+		{
+			"Categories are normalized",
+			CabrilloHeader{Contest: "ARRL-RTTY", CategoryOperator: "single-op", CategoryPower: " qrp", CategoryBand: "20m"},
+			CabrilloHeader{Contest: "ARRL-RTTY", CategoryOperator: "SINGLE-OP", CategoryPower: "QRP", CategoryBand: "20M"},
+			false,
+		},
+		{
+			"Band designator category",
+			CabrilloHeader{Contest: "ARRL-VHF", CategoryBand: "1.2g"},
+			CabrilloHeader{Contest: "ARRL-VHF", CategoryBand: "1.2G"},
+			false,
+		},
+		{
+			"Invalid band category",
+			CabrilloHeader{Contest: "ARRL-RTTY", CategoryBand: "30m"},
+			CabrilloHeader{Contest: "ARRL-RTTY", CategoryBand: "30M"},
+			true,
+		},
+		{
+			"Missing contest",
+			CabrilloHeader{CategoryOperator: "SINGLE-OP"},
+			CabrilloHeader{CategoryOperator: "SINGLE-OP"},
+			true,
+		},
+		{
+			"Invalid category",
+			CabrilloHeader{Contest: "ARRL-RTTY", CategoryStation: "garden"},
+			CabrilloHeader{Contest: "ARRL-RTTY", CategoryStation: "GARDEN"},
+			true,
+		},
+		{
+			"Negative claimed score",
+			CabrilloHeader{Contest: "ARRL-RTTY", ClaimedScore: -1},
+			CabrilloHeader{Contest: "ARRL-RTTY", ClaimedScore: -1},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCabrilloHeader(&tt.cabrilloHeader)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCabrilloHeader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.cabrilloHeader != tt.wantHeader {
+				t.Errorf("validateCabrilloHeader() header = %v, want %v", tt.cabrilloHeader, tt.wantHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// outputCabrillo generates and writes data in Cabrillo format
+func outputCabrillo(outputFile string, fullLog []LogLine, cabrilloHeader CabrilloHeader) {
+
+	//convert the log data to an in-memory Cabrillo file
+	cabrilloData := buildCabrillo(fullLog, cabrilloHeader)
+
+	//write to a file (re-using function defined to write adif file)
+	writeFile(outputFile, cabrilloData)
+}
+
+// buildCabrillo creates the Cabrillo file in memory ready to be printed
+func buildCabrillo(fullLog []LogLine, cabrilloHeader CabrilloHeader) (cabrilloList []string) {
+	cabrilloList = append(cabrilloList, "START-OF-LOG: 3.0")
+	cabrilloList = append(cabrilloList, "CREATED-BY: FLEcli")
+	if len(fullLog) > 0 {
+		cabrilloList = appendCabrilloTag(cabrilloList, "CALLSIGN", fullLog[0].MyCall)
+	}
+	cabrilloList = appendCabrilloTag(cabrilloList, "CONTEST", cabrilloHeader.Contest)
+	cabrilloList = appendCabrilloTag(cabrilloList, "CATEGORY-OPERATOR", cabrilloHeader.CategoryOperator)
+	cabrilloList = appendCabrilloTag(cabrilloList, "CATEGORY-ASSISTED", cabrilloHeader.CategoryAssisted)
+	cabrilloList = appendCabrilloTag(cabrilloList, "CATEGORY-BAND", cabrilloHeader.CategoryBand)
+	cabrilloList = appendCabrilloTag(cabrilloList, "CATEGORY-MODE", cabrilloHeader.CategoryMode)
+	cabrilloList = appendCabrilloTag(cabrilloList, "CATEGORY-POWER", cabrilloHeader.CategoryPower)
+	cabrilloList = appendCabrilloTag(cabrilloList, "CATEGORY-STATION", cabrilloHeader.CategoryStation)
+	cabrilloList = appendCabrilloTag(cabrilloList, "CATEGORY-TRANSMITTER", cabrilloHeader.CategoryTransmitter)
+	if cabrilloHeader.ClaimedScore > 0 {
+		cabrilloList = appendCabrilloTag(cabrilloList, "CLAIMED-SCORE", strconv.Itoa(cabrilloHeader.ClaimedScore))
+	}
+
+	//If not specified, the operators are taken from the log
+	operators := cabrilloHeader.Operators
+	if operators == "" && len(fullLog) > 0 {
+		operators = fullLog[0].Operator
+	}
+	cabrilloList = appendCabrilloTag(cabrilloList, "OPERATORS", strings.ToUpper(operators))
+	cabrilloList = appendCabrilloTag(cabrilloList, "LOCATION", cabrilloHeader.Location)
+	if len(fullLog) > 0 {
+		cabrilloList = appendCabrilloTag(cabrilloList, "GRID-LOCATOR", fullLog[0].MyGrid)
+	}
+
+	for _, logLine := range fullLog {
+		//The QSO lines are positional: a QSO with an invalid value is skipped
+		if !logLine.isExportable() || !logLine.IsValid(FieldRSTsent) || !logLine.IsValid(FieldRSTrcvd) {
+			continue
+		}
+		cabrilloList = append(cabrilloList, cabrilloQso(logLine))
+	}
+
+	cabrilloList = append(cabrilloList, "END-OF-LOG:")
+	return cabrilloList
+}
+
+// appendCabrilloTag adds a header line if a value is available
+func appendCabrilloTag(cabrilloList []string, tag, value string) []string {
+	if value == "" {
+		return cabrilloList
+	}
+	return append(cabrilloList, fmt.Sprintf("%s: %s", tag, value))
+}
+
+// Frequency, mode, date, time, my call, report sent, exchange sent, call, report rcvd, exchange rcvd
+var cabrilloQsoFormat = "QSO: %5s %-2s %s %s %-13s %-3s %-6s %-13s %-3s %s"
+
+// cabrilloQso generates a QSO line
+func cabrilloQso(logLine LogLine) string {
+	qso := fmt.Sprintf(cabrilloQsoFormat,
+		cabrilloFrequency(logLine),
+		cabrilloMode(logLine.Mode),
+		logLine.Date,
+		logLine.Time,
+		logLine.MyCall,
+		logLine.RSTsent,
+		sprintExchange(logLine.STX, logLine.STXstring),
+		logLine.Call,
+		logLine.RSTrcvd,
+		sprintExchange(logLine.SRX, logLine.SRXstring))
+	return strings.TrimRight(qso, " ")
+}
+
+// cabrilloBandDesignators maps the bands from 50MHz and above to their Cabrillo 3.0 designator
+var cabrilloBandDesignators = map[string]string{
+	"6m":     "50",
+	"4m":     "70",
+	"2m":     "144",
+	"1.25m":  "222",
+	"70cm":   "432",
+	"33cm":   "902",
+	"23cm":   "1.2G",
+	"13cm":   "2.3G",
+	"9cm":    "3.4G",
+	"6cm":    "5.7G",
+	"3cm":    "10G",
+	"1.25cm": "24G",
+	"6mm":    "47G",
+	"4mm":    "75G",
+	"2.5mm":  "119G",
+	"2mm":    "134G",
+	"1mm":    "241G",
+}
+
+// cabrilloFrequency returns the frequency in kHz for the HF bands or the band designator for 50MHz and above
+func cabrilloFrequency(logLine LogLine) string {
+	if designator, isFound := cabrilloBandDesignators[strings.ToLower(logLine.Band)]; isFound {
+		return designator
+	}
+
+	if logLine.Frequency != "" {
+		if qrg, err := strconv.ParseFloat(logLine.Frequency, 64); err == nil {
+			return fmt.Sprintf("%.0f", qrg*1000)
+		}
+	}
+
+	//No frequency available: use the lower limit of the band
+	_, lowerLimit, _, _ := IsBand(logLine.Band)
+	return fmt.Sprintf("%.0f", lowerLimit*1000)
+}
+
+// cabrilloMode converts the FLE mode to the Cabrillo mode
+func cabrilloMode(mode string) string {
+	//The submodes (USB, PCW, ...) are grouped with their mode
+	if definition, isMode := fleModes[mode]; isMode {
+		mode = definition.AdifMode
+	}
+	switch mode {
+	case "CW":
+		return "CW"
+	case "SSB", "AM":
+		return "PH"
+	case "FM":
+		return "FM"
+	case "RTTY":
+		return "RY"
+	}
+	return "DG"
+}
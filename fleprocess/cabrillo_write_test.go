@@ -0,0 +1,153 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_buildCabrillo(t *testing.T) {
+	sampleFilledLog1 := []LogLine{
+		{MyCall: "WB9ZZZ", Operator: "wb9zzz", Call: "WY7FD", Date: "2016-01-02", Time: "1839", Band: "15m", Frequency: "21.085", Mode: "RTTY", RSTsent: "599", RSTrcvd: "599", STX: "33", SRXstring: "WY"},
+		{MyCall: "WB9ZZZ", Operator: "wb9zzz", Call: "KU1T", Date: "2016-01-02", Time: "1856", Band: "20m", Mode: "RTTY", RSTsent: "599", RSTrcvd: "599", STX: "34", SRXstring: "WV"},
+	}
+
+	expectedOutput1 := []string{
+		"START-OF-LOG: 3.0",
+		"CREATED-BY: FLEcli",
+		"CALLSIGN: WB9ZZZ",
+		"CONTEST: ARRL-RTTY",
+		"CATEGORY-OPERATOR: SINGLE-OP",
+		"CATEGORY-POWER: LOW",
+		"CLAIMED-SCORE: 42",
+		"OPERATORS: WB9ZZZ",
+		"LOCATION: WI",
+		"QSO: 21085 RY 2016-01-02 1839 WB9ZZZ        599 33     WY7FD         599 WY",
+		"QSO: 14000 RY 2016-01-02 1856 WB9ZZZ        599 34     KU1T          599 WV",
+		"END-OF-LOG:",
+	}
+
+	sampleFilledLog2 := []LogLine{
+		{MyCall: "WB9ZZZ", MyGrid: "EN52", Call: "OK1DOL", Date: "2015-12-18", Time: "1924", Band: "160m", Mode: "CW", RSTsent: "599", RSTrcvd: "599", STXstring: "JN58", SRXstring: "JN69"},
+		{MyCall: "WB9ZZZ", MyGrid: "EN52", Call: "E77DX", Date: "2015-12-18", Time: "1926", Band: "2m", Mode: "FM", RSTsent: "59", RSTrcvd: "59", STXstring: "JN58", SRXstring: "JN84"},
+	}
+
+	expectedOutput2 := []string{
+		"START-OF-LOG: 3.0",
+		"CREATED-BY: FLEcli",
+		"CALLSIGN: WB9ZZZ",
+		"CONTEST: STEW-PERRY",
+		"OPERATORS: K9ABC",
+		"GRID-LOCATOR: EN52",
+		"QSO:  1800 CW 2015-12-18 1924 WB9ZZZ        599 JN58   OK1DOL        599 JN69",
+		"QSO:   144 FM 2015-12-18 1926 WB9ZZZ        59  JN58   E77DX         59  JN84",
+		"END-OF-LOG:",
+	}
+
+	type args struct {
+		fullLog        []LogLine
+		cabrilloHeader CabrilloHeader
+	}
+	tests := []struct {
+		name             string
+		args             args
+		wantCabrilloList []string
+	}{
+		{
+			"Serial numbers and exchange",
+			args{fullLog: sampleFilledLog1, cabrilloHeader: CabrilloHeader{Contest: "ARRL-RTTY", CategoryOperator: "SINGLE-OP", CategoryPower: "LOW", ClaimedScore: 42, Location: "WI"}},
+			expectedOutput1,
+		},
+		{
+			"Grid exchange and VHF",
+			args{fullLog: sampleFilledLog2, cabrilloHeader: CabrilloHeader{Contest: "STEW-PERRY", Operators: "k9abc"}},
+			expectedOutput2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotCabrilloList := buildCabrillo(tt.args.fullLog, tt.args.cabrilloHeader); !reflect.DeepEqual(gotCabrilloList, tt.wantCabrilloList) {
+				t.Errorf("buildCabrillo() = %v, want %v", gotCabrilloList, tt.wantCabrilloList)
+			}
+		})
+	}
+}
+
+func Test_cabrilloMode(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"CW", "CW"},
+		{"SSB", "PH"},
+		{"AM", "PH"},
+		{"FM", "FM"},
+		{"RTTY", "RY"},
+		{"FT8", "DG"},
+		{"USB", "PH"},
+		{"PCW", "CW"},
+		{"ASCI", "RY"},
+		{"FT4", "DG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if got := cabrilloMode(tt.mode); got != tt.want {
+				t.Errorf("cabrilloMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cabrilloFrequency(t *testing.T) {
+	tests := []struct {
+		logLine LogLine
+		want    string
+	}{
+		{LogLine{Band: "40m", Frequency: "7.018"}, "7018"},
+		{LogLine{Band: "20m"}, "14000"},
+		{LogLine{Band: "6m", Frequency: "50.150"}, "50"},
+		{LogLine{Band: "4m"}, "70"},
+		{LogLine{Band: "2m"}, "144"},
+		{LogLine{Band: "1.25m"}, "222"},
+		{LogLine{Band: "70cm"}, "432"},
+		{LogLine{Band: "33cm"}, "902"},
+		{LogLine{Band: "23cm"}, "1.2G"},
+		{LogLine{Band: "13cm"}, "2.3G"},
+		{LogLine{Band: "9cm"}, "3.4G"},
+		{LogLine{Band: "6cm"}, "5.7G"},
+		{LogLine{Band: "3cm"}, "10G"},
+		{LogLine{Band: "1.25cm"}, "24G"},
+		{LogLine{Band: "6mm"}, "47G"},
+		{LogLine{Band: "4mm"}, "75G"},
+		{LogLine{Band: "2.5mm"}, "119G"},
+		{LogLine{Band: "2mm"}, "134G"},
+		{LogLine{Band: "1mm"}, "241G"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.logLine.Band, func(t *testing.T) {
+			if got := cabrilloFrequency(tt.logLine); got != tt.want {
+				t.Errorf("cabrilloFrequency() = %v, want %v", got, tt.want)
+			}
+			//The band designators are accepted as CATEGORY-BAND (except for 6m, 4m and 2m, written with their band name)
+			if designator, isFound := cabrilloBandDesignators[tt.logLine.Band]; isFound && !isInList(designator, cabrilloCategoryBands) && !isInList(strings.ToUpper(tt.logLine.Band), cabrilloCategoryBands) {
+				t.Errorf("%s is not a valid CATEGORY-BAND", designator)
+			}
+		})
+	}
+}
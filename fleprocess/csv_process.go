@@ -20,31 +20,87 @@ limitations under the License.
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
-//ProcessCsvCommand loads an FLE input to produce a SOTA CSV
-func ProcessCsvCommand(inputFilename, outputFilename string, isInterpolateTime, isOverwriteCsv bool) error {
+//CsvParams is holding all the parameters required to generate a SOTA CSV file
+type CsvParams struct {
+	InputFilename     string
+	OutputFilename    string
+	IsInterpolateTime bool
+	IsOverwrite       bool
+	IsSplit           bool
+	IsChaser          bool
+	IsS2S             bool
+}
+
+//ProcessCsvCommand loads an FLE input to produce a SOTA CSV (activator or chaser log).
+//If IsSplit is set, one file is generated per activation (summit and UTC day).
+//If IsS2S is set, only the summit to summit QSOs are exported (and listed).
+func ProcessCsvCommand(csvParams CsvParams) error {
+
+	if csvParams.IsChaser && csvParams.IsS2S {
+		return fmt.Errorf("A chaser log and a summit to summit log can't be generated at the same time")
+	}
 
-	//Validate of build the output filenaem
+	//Validate of build the output filenaem (or directory when splitting the output)
 	var verifiedOutputFilename string
 	var err error
 
-	if verifiedOutputFilename, err = buildOutputFilename(outputFilename, inputFilename, isOverwriteCsv, ".csv"); err != nil {
-		return err
+	if csvParams.IsSplit {
+		if verifiedOutputFilename, err = buildOutputDirectory(csvParams.OutputFilename, csvParams.InputFilename); err != nil {
+			return err
+		}
+	} else {
+		if verifiedOutputFilename, err = buildOutputFilename(csvParams.OutputFilename, csvParams.InputFilename, csvParams.IsOverwrite, ".csv"); err != nil {
+			return err
+		}
+	}
+
+	//An activator log that is not split is streamed to the output file
+	if !(csvParams.IsSplit || csvParams.IsChaser || csvParams.IsS2S) {
+		return streamLogToFile(csvParams.InputFilename, csvParams.IsInterpolateTime, verifiedOutputFilename, newSotaCsvChecker(),
+			func(writer io.Writer) LogWriter { return NewCsvWriter(writer) }, "CSV")
 	}
 
 	//Load the input file
 	var loadedLogFile []LogLine
 	var isLoadedOK bool
 
-	if loadedLogFile, isLoadedOK = LoadFile(inputFilename, isInterpolateTime); isLoadedOK == false {
+	if loadedLogFile, isLoadedOK = LoadFile(csvParams.InputFilename, csvParams.IsInterpolateTime); isLoadedOK == false {
 		return fmt.Errorf("There were input file parsing errors. Could not generate CSV file")
 	}
 
 	//Check if we have all the necessary data
-	if err := validateDataForSotaCsv(loadedLogFile); err != nil {
-		return err
+	if csvParams.IsChaser {
+		loadedLogFile = selectSotaChaserQsos(loadedLogFile)
+		if err := validateDataForSotaChaserCsv(loadedLogFile); err != nil {
+			return err
+		}
+	} else {
+		if err := validateDataForSotaCsv(loadedLogFile); err != nil {
+			return err
+		}
+	}
+
+	if csvParams.IsS2S {
+		fmt.Print("\n" + SprintS2sReport(loadedLogFile))
+		if loadedLogFile = selectS2sQsos(loadedLogFile); len(loadedLogFile) == 0 {
+			return fmt.Errorf("No summit to summit QSO found. Could not generate S2S CSV file")
+		}
+	}
+
+	if csvParams.IsSplit {
+		activationLogs := splitLogPerActivation(loadedLogFile, verifiedOutputFilename, ".csv", []refSelector{selectMySOTA})
+		//Check all the file names before writing anything
+		if err := verifyOutputFilenames(activationLogs, csvParams.InputFilename, csvParams.IsOverwrite, ".csv"); err != nil {
+			return err
+		}
+		for _, activationLog := range activationLogs {
+			outputCsv(activationLog.outputFilename, activationLog.log)
+		}
+		return nil
 	}
 
 	outputCsv(verifiedOutputFilename, loadedLogFile)
@@ -53,81 +109,80 @@ func ProcessCsvCommand(inputFilename, outputFilename string, isInterpolateTime,
 
 }
 
-//validateDataForSotaCsv checks whether all the requiered data is present in the supplied data
-func validateDataForSotaCsv(loadedLogFile []LogLine) error {
-	if len(loadedLogFile) == 0 {
-		return fmt.Errorf("No QSO found")
+//selectSotaChaserQsos returns the QSOs made with a SOTA activator.
+//As the chaser layout has no summit of its own, MySOTA is cleared.
+func selectSotaChaserQsos(loadedLogFile []LogLine) (chaserLog []LogLine) {
+	for _, logLine := range loadedLogFile {
+		if logLine.SOTA == "" {
+			continue
+		}
+		logLine.MySOTA = ""
+		chaserLog = append(chaserLog, logLine)
 	}
+	return chaserLog
+}
 
-	isNoMySota := false
-	//MySOTA and MyCall are header values. If missing on the first line, it will be missing at every line
-	if loadedLogFile[0].MySOTA == "" {
-		//if not set, we might be dealing with a chaser log
-		isNoMySota = true
-	}
-	if loadedLogFile[0].MyCall == "" {
-		return fmt.Errorf("Missing MyCall")
+//validateDataForSotaChaserCsv checks the selected chaser QSOs and their summit reference
+func validateDataForSotaChaserCsv(chaserLog []LogLine) error {
+	if len(chaserLog) == 0 {
+		return fmt.Errorf("No QSO with a SOTA reference found")
 	}
 
 	var errorsBuffer strings.Builder
-	//We accumulate the errors messages
-	for i := 0; i < len(loadedLogFile); i++ {
-
-		//Compute the error location for a meaning full error
-		var errorLocation string
-		if loadedLogFile[i].Time == "" {
-			errorLocation = fmt.Sprintf("for log entry #%d", i+1)
-		} else {
-			errorLocation = fmt.Sprintf("for log entry at %s (#%d)", loadedLogFile[i].Time, i+1)
-		}
-
-		if loadedLogFile[i].Date == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
-			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing date %s", errorLocation))
-		}
-		if loadedLogFile[i].Band == "" {
+	for i, logLine := range chaserLog {
+		if _, diagnostic := ValidateSota(logLine.SOTA); diagnostic != nil {
 			if errorsBuffer.String() != "" {
 				errorsBuffer.WriteString(fmt.Sprintf(", "))
 			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing band %s", errorLocation))
+			errorsBuffer.WriteString(fmt.Sprintf("%s for chased QSO with %s at %s (#%d)", diagnostic.Message, logLine.Call, logLine.Time, i+1))
 		}
-		if loadedLogFile[i].Mode == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
+	}
+	if errorsBuffer.String() != "" {
+		return fmt.Errorf(errorsBuffer.String())
+	}
+
+	return validateDataForSotaCsv(chaserLog)
+}
+
+//validateDataForSotaCsv checks whether all the requiered data is present in the supplied data
+func validateDataForSotaCsv(loadedLogFile []LogLine) error {
+	checker := newSotaCsvChecker()
+	for _, logLine := range loadedLogFile {
+		checker.check(logLine)
+	}
+	return checker.err()
+}
+
+//newSotaCsvChecker creates a checker for the data required by the SOTA CSV file
+func newSotaCsvChecker() *logChecker {
+	isFirstLine := true
+	isNoMySota := false
+
+	return &logChecker{
+		//MyCall can be redefined in the log, so it must be checked for every segment
+		checkSegmentHeader: func(logLine LogLine, location string) error {
+			if logLine.MyCall == "" {
+				return fmt.Errorf("Missing MyCall%s", location)
 			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing mode %s", errorLocation))
-		}
-		if loadedLogFile[i].Call == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
+			if field, isInvalid := firstInvalidField(logLine, headerFields); isInvalid {
+				return fmt.Errorf("Invalid %s value%s", field, location)
 			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing call %s", errorLocation))
-		}
-		if loadedLogFile[i].Time == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
+			return nil
+		},
+		checkLine: func(logLine LogLine, errorLocation string) (lineErrors []string) {
+			//MySOTA is a header value. If missing on the first line, we might be dealing with a chaser log
+			if isFirstLine {
+				isNoMySota = logLine.MySOTA == ""
+				isFirstLine = false
 			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing QSO time %s", errorLocation))
-		}
-		//FIXME: if isNoMySota and MySota defined means that it was defined later in the log file
-		if isNoMySota && loadedLogFile[i].MySOTA != "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
+			//A SOTA CSV file is either an activator or a chaser log: a MySota defined later in the log can't be processed
+			if isNoMySota && logLine.MySOTA != "" {
+				lineErrors = append(lineErrors, fmt.Sprintf("encountered an unexpexted MySota reference while processing what should be a chaser log %s", errorLocation))
 			}
-			errorsBuffer.WriteString(fmt.Sprintf("encountered an unexpexted MySota reference while processing what should be a chaser log %s", errorLocation))
-		}
-
-		if isNoMySota && loadedLogFile[i].SOTA == "" {
-			if errorsBuffer.String() != "" {
-				errorsBuffer.WriteString(fmt.Sprintf(", "))
+			if isNoMySota && logLine.SOTA == "" {
+				lineErrors = append(lineErrors, fmt.Sprintf("missing SOTA reference while attempting to process chaser log %s", errorLocation))
 			}
-			errorsBuffer.WriteString(fmt.Sprintf("missing SOTA reference while attempting to process chaser log %s", errorLocation))
-		}
+			return lineErrors
+		},
 	}
-	if errorsBuffer.String() != "" {
-		return fmt.Errorf(errorsBuffer.String())
-	}
-	return nil
 }
@@ -2,6 +2,7 @@ package fleprocess
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -107,10 +108,7 @@ func Test_validateDataForSotaCsv(t *testing.T) {
 
 func TestProcessCsvCommand(t *testing.T) {
 	type args struct {
-		inputFilename     string
-		outputCsvFilename string
-		isInterpolateTime bool
-		isOverwriteCsv    bool
+		csvParams CsvParams
 	}
 	tests := []struct {
 		name    string
@@ -119,25 +117,100 @@ func TestProcessCsvCommand(t *testing.T) {
 	}{
 		{
 			"Bad output filename (directory)",
-			args{inputFilename: "../test/data/fle-4-no-qso.txt", outputCsvFilename: "../test/data", isInterpolateTime: false, isOverwriteCsv: false},
+			args{csvParams: CsvParams{InputFilename: "../test/data/fle-4-no-qso.txt", OutputFilename: "../test/data", IsInterpolateTime: false, IsOverwrite: false}},
 			true,
 		},
 		{
 			"input file parsing errors",
-			args{inputFilename: "../test/data/fle-3-error.txt", outputCsvFilename: "", isInterpolateTime: false, isOverwriteCsv: false},
+			args{csvParams: CsvParams{InputFilename: "../test/data/fle-3-error.txt", OutputFilename: "", IsInterpolateTime: false, IsOverwrite: false}},
 			true,
 		},
 		{
 			"No QSO in loaded file",
-			args{inputFilename: "../test/data/fle-4-no-qso.txt", outputCsvFilename: "", isInterpolateTime: false, isOverwriteCsv: false},
+			args{csvParams: CsvParams{InputFilename: "../test/data/fle-4-no-qso.txt", OutputFilename: "", IsInterpolateTime: false, IsOverwrite: false}},
+			true,
+		},
+		{
+			"No QSO in loaded file (chaser)",
+			args{csvParams: CsvParams{InputFilename: "../test/data/fle-4-no-qso.txt", OutputFilename: "", IsInterpolateTime: false, IsOverwrite: false, IsChaser: true}},
+			true,
+		},
+		{
+			"Chaser and S2S at the same time",
+			args{csvParams: CsvParams{InputFilename: "../test/data/fle-1.txt", OutputFilename: "", IsChaser: true, IsS2S: true}},
 			true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ProcessCsvCommand(tt.args.inputFilename, tt.args.outputCsvFilename, tt.args.isInterpolateTime, tt.args.isOverwriteCsv); (err != nil) != tt.wantErr {
+			if err := ProcessCsvCommand(tt.args.csvParams); (err != nil) != tt.wantErr {
 				t.Errorf("ProcessCsvCommand() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func Test_selectSotaChaserQsos(t *testing.T) {
+	sampleLog := []LogLine{
+		{Date: "date", MyCall: "myCall", MySOTA: "ON/ON-001", Mode: "mode", Band: "band", Time: "12:01", Call: "call1"},
+		{Date: "date", MyCall: "myCall", MySOTA: "ON/ON-001", Mode: "mode", Band: "band", Time: "12:02", Call: "call2", SOTA: "G/LD-001"},
+		{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:03", Call: "call3", SOTA: "HB/BE-001"},
+	}
+	expectedLog := []LogLine{
+		{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:02", Call: "call2", SOTA: "G/LD-001"},
+		{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:03", Call: "call3", SOTA: "HB/BE-001"},
+	}
+
+	if gotLog := selectSotaChaserQsos(sampleLog); !reflect.DeepEqual(gotLog, expectedLog) {
+		t.Errorf("selectSotaChaserQsos() = %v, want %v", gotLog, expectedLog)
+	}
+}
+
+func Test_validateDataForSotaChaserCsv(t *testing.T) {
+	tests := []struct {
+		name      string
+		chaserLog []LogLine
+		want      error
+	}{
+		{
+			"Happy case",
+			[]LogLine{
+				{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:02", Call: "call2", SOTA: "G/LD-001"}},
+			nil,
+		},
+		{
+			"No chased summit",
+			nil,
+			fmt.Errorf("No QSO with a SOTA reference found"),
+		},
+		{
+			"Invalid summit reference",
+			[]LogLine{
+				{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:02", Call: "call2", SOTA: "G/LD-001"},
+				{Date: "date", MyCall: "myCall", Mode: "mode", Band: "band", Time: "12:03", Call: "call3", SOTA: "Sota3"}},
+			fmt.Errorf("[SOTA3] is an invalid SOTA reference for chased QSO with call3 at 12:03 (#2)"),
+		},
+		{
+			"Missing data",
+			[]LogLine{
+				{Date: "date", MyCall: "myCall", Mode: "", Band: "band", Time: "12:02", Call: "call2", SOTA: "G/LD-001"}},
+			fmt.Errorf("missing mode for log entry at 12:02 (#1)"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateDataForSotaChaserCsv(tt.chaserLog)
+
+			//Test the error message, if any
+			if got != nil && tt.want != nil {
+				if got.Error() != tt.want.Error() {
+					t.Errorf("validateDataForSotaChaserCsv() = %v, want %v", got, tt.want)
+				}
+			} else {
+				if !(got == nil && tt.want == nil) {
+					t.Errorf("validateDataForSotaChaserCsv() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
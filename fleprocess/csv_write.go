@@ -17,7 +17,9 @@ limitations under the License.
 */
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 )
@@ -38,31 +40,67 @@ func buildCsv(fullLog []LogLine) (csvList []string) {
 	// V2,ON4KJM/P,ON/ON-001,24/05/20,1310,14MHz,CW,S57LC
 
 	for _, logLine := range fullLog {
-		var csvLine strings.Builder
-		csvLine.WriteString("V2,")
-		csvLine.WriteString(fmt.Sprintf("%s", logLine.MyCall))
-		csvLine.WriteString(fmt.Sprintf(",%s", logLine.MySOTA))
-		csvLine.WriteString(fmt.Sprintf(",%s", csvDate(logLine.Date)))
-		csvLine.WriteString(fmt.Sprintf(",%s", logLine.Time))
-		//TODO: Should we test the result
-		_, _, _, sotaBand := IsBand(logLine.Band)
-		csvLine.WriteString(fmt.Sprintf(",%s", sotaBand))
-		csvLine.WriteString(fmt.Sprintf(",%s", logLine.Mode))
-		csvLine.WriteString(fmt.Sprintf(",%s", logLine.Call))
-		if logLine.SOTA != "" {
-			csvLine.WriteString(fmt.Sprintf(",%s", logLine.SOTA))
-		} else {
-			if logLine.Comment != "" {
-				csvLine.WriteString(",")
-			}
+		if csvRecord, isExportable := buildCsvRecord(logLine); isExportable {
+			csvList = append(csvList, csvRecord)
 		}
+	}
+	return csvList
+}
+
+// buildCsvRecord converts a QSO to a SOTA CSV line.
+// QSOs with an invalid date, call or summit reference can't be exported (isExportable is false).
+func buildCsvRecord(logLine LogLine) (csvRecord string, isExportable bool) {
+	if !logLine.isExportable() || !logLine.IsValid(FieldMySOTA) {
+		return "", false
+	}
+	var csvLine strings.Builder
+	csvLine.WriteString("V2,")
+	csvLine.WriteString(fmt.Sprintf("%s", logLine.MyCall))
+	csvLine.WriteString(fmt.Sprintf(",%s", logLine.MySOTA))
+	csvLine.WriteString(fmt.Sprintf(",%s", csvDate(logLine.Date)))
+	csvLine.WriteString(fmt.Sprintf(",%s", logLine.Time))
+	//TODO: Should we test the result
+	_, _, _, sotaBand := IsBand(logLine.Band)
+	csvLine.WriteString(fmt.Sprintf(",%s", sotaBand))
+	csvLine.WriteString(fmt.Sprintf(",%s", logLine.Mode))
+	csvLine.WriteString(fmt.Sprintf(",%s", logLine.Call))
+	if logLine.SOTA != "" {
+		csvLine.WriteString(fmt.Sprintf(",%s", logLine.SOTA))
+	} else {
 		if logLine.Comment != "" {
-			csvLine.WriteString(fmt.Sprintf(",%s", logLine.Comment))
+			csvLine.WriteString(",")
 		}
+	}
+	if logLine.Comment != "" {
+		csvLine.WriteString(fmt.Sprintf(",%s", logLine.Comment))
+	}
+
+	return csvLine.String(), true
+}
+
+// CsvWriter writes a SOTA CSV file one QSO at a time
+type CsvWriter struct {
+	writer *bufio.Writer
+}
+
+// NewCsvWriter creates a SOTA CSV writer sending its output to the supplied writer
+func NewCsvWriter(writer io.Writer) *CsvWriter {
+	return &CsvWriter{writer: bufio.NewWriter(writer)}
+}
 
-		csvList = append(csvList, csvLine.String())
+// Write converts the QSO to a CSV line and writes it. QSOs that can't be exported are skipped.
+func (csvWriter *CsvWriter) Write(logLine LogLine) error {
+	csvRecord, isExportable := buildCsvRecord(logLine)
+	if !isExportable {
+		return nil
 	}
-	return csvList
+	_, err := csvWriter.writer.WriteString(csvRecord + "\n")
+	return err
+}
+
+// Flush writes the buffered data
+func (csvWriter *CsvWriter) Flush() error {
+	return csvWriter.writer.Flush()
 }
 
 //adifDate converts a date in YYYY-MM-DD format to YYYYMMDD
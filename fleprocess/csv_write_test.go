@@ -17,6 +17,7 @@ limitations under the License.
 */
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 )
@@ -78,6 +79,17 @@ func Test_buildCsv(t *testing.T) {
 		"V2,ON4KJM/P,,24/05/20,1312,14MHz,CW,ON4LY,ON/ON-003,QSL Message",
 	}
 
+	//QSOs with an invalid call or summit are skipped
+	invalidLog := []LogLine{
+		{MyCall: "ON4KJM/P", Call: "FOOBAR", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW", MySOTA: "ON/ON-001", InvalidFields: FieldSet(0).with(FieldCall, true)},
+		{MyCall: "ON4KJM/P", Call: "ON4LY", Date: "2020-05-24", Time: "1312", Band: "20m", Mode: "CW", MySOTA: "ON/ON-001"},
+		{MyCall: "ON4KJM/P", Call: "ON4LY", Date: "2020-05-24", Time: "1312", Band: "20m", Mode: "CW", MySOTA: "ON/ON-1", InvalidFields: FieldSet(0).with(FieldMySOTA, true)},
+	}
+
+	expectedInvalidOutput := []string{
+		"V2,ON4KJM/P,ON/ON-001,24/05/20,1312,14MHz,CW,ON4LY",
+	}
+
 	type args struct {
 		fullLog []LogLine
 	}
@@ -101,6 +113,11 @@ func Test_buildCsv(t *testing.T) {
 			args{fullLog: chaserLog},
 			expectedChaserOutput3,
 		},
+		{
+			"Invalid values",
+			args{fullLog: invalidLog},
+			expectedInvalidOutput,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -110,3 +127,27 @@ func Test_buildCsv(t *testing.T) {
 		})
 	}
 }
+
+func TestCsvWriter(t *testing.T) {
+	fullLog := []LogLine{
+		{MyCall: "ON4KJM/P", Call: "S57LC", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW", MySOTA: "ON/ON-001", SOTA: "ON/ON-002"},
+		{MyCall: "ON4KJM/P", Call: "FOOBAR", Date: "2020-05-24", Time: "1311", Band: "20m", Mode: "CW", MySOTA: "ON/ON-001", InvalidFields: FieldSet(0).with(FieldCall, true)},
+		{MyCall: "ON4KJM/P", Call: "ON4LY", Date: "2020-05-24", Time: "1312", Band: "20m", Mode: "CW", MySOTA: "ON/ON-001", Comment: "tnx"},
+	}
+	wantOutput := "V2,ON4KJM/P,ON/ON-001,24/05/20,1310,14MHz,CW,S57LC,ON/ON-002\n" +
+		"V2,ON4KJM/P,ON/ON-001,24/05/20,1312,14MHz,CW,ON4LY,,tnx\n"
+
+	var output bytes.Buffer
+	csvWriter := NewCsvWriter(&output)
+	for _, logLine := range fullLog {
+		if err := csvWriter.Write(logLine); err != nil {
+			t.Fatalf("CsvWriter.Write() unexpected error: %v", err)
+		}
+	}
+	if err := csvWriter.Flush(); err != nil {
+		t.Fatalf("CsvWriter.Flush() unexpected error: %v", err)
+	}
+	if gotOutput := output.String(); gotOutput != wantOutput {
+		t.Errorf("CsvWriter output = %v, want %v", gotOutput, wantOutput)
+	}
+}
@@ -0,0 +1,132 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import "fmt"
+
+//Severity tells how serious a problem found while loading a log is
+type Severity int
+
+const (
+	//SeverityWarning is a problem that doesn't prevent the log from being used
+	SeverityWarning Severity = iota
+	//SeverityError is a problem that makes the loaded log unusable
+	SeverityError
+	//SeverityFatal is a problem that stopped the processing of the log
+	SeverityFatal
+)
+
+//String returns the name of the severity
+func (severity Severity) String() string {
+	switch severity {
+	case SeverityWarning:
+		return "Warning"
+	case SeverityError:
+		return "Error"
+	case SeverityFatal:
+		return "Fatal error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(severity))
+}
+
+//MarshalText gives the severity name used in the JSON report ("warning", "error" or "fatal")
+func (severity Severity) MarshalText() ([]byte, error) {
+	switch severity {
+	case SeverityWarning:
+		return []byte("warning"), nil
+	case SeverityError:
+		return []byte("error"), nil
+	case SeverityFatal:
+		return []byte("fatal"), nil
+	}
+	return nil, fmt.Errorf("Unknown severity %d", int(severity))
+}
+
+//DiagnosticCode identifies the kind of problem reported by a Diagnostic
+type DiagnosticCode string
+
+//Diagnostic codes
+const (
+	CodeFileAccess         DiagnosticCode = "file-access"
+	CodeHeaderRedefinition DiagnosticCode = "header-redefinition"
+	CodeInvalidCall        DiagnosticCode = "invalid-call"
+	CodeInvalidWwff        DiagnosticCode = "invalid-wwff"
+	CodeInvalidSota        DiagnosticCode = "invalid-sota"
+	CodeInvalidPota        DiagnosticCode = "invalid-pota"
+	CodeDuplicateReference DiagnosticCode = "duplicate-reference"
+	CodeInvalidGrid        DiagnosticCode = "invalid-grid"
+	CodeInvalidDate        DiagnosticCode = "invalid-date"
+	CodeInvalidFrequency   DiagnosticCode = "invalid-frequency"
+	CodeInvalidBand        DiagnosticCode = "invalid-band"
+	CodeInvalidRegion      DiagnosticCode = "invalid-region"
+	CodeInvalidReport      DiagnosticCode = "invalid-report"
+	CodeInvalidSerial      DiagnosticCode = "invalid-serial"
+	CodeUnknownElement     DiagnosticCode = "unknown-element"
+	CodeTimeInterpolation  DiagnosticCode = "time-interpolation"
+	CodeUnknownField       DiagnosticCode = "unknown-field"
+	CodeDuplicateField     DiagnosticCode = "duplicate-field"
+	CodeMissingField       DiagnosticCode = "missing-field"
+	CodeInvalidValue       DiagnosticCode = "invalid-value"
+	CodeInvalidDataType    DiagnosticCode = "invalid-data-type"
+	CodeTooManyDiagnostics DiagnosticCode = "too-many-diagnostics"
+)
+
+//Diagnostic describes a problem found while loading a FLE log (or validating an ADIF file)
+type Diagnostic struct {
+	//Line is the line number in the input (starting at 1). It is 0 if the problem is not related to a line.
+	Line int `json:"line"`
+	//Column is the position (starting at 1) of the offending element in the line. It is 0 if unknown.
+	Column int `json:"column"`
+	//Length is the length of the offending element
+	Length   int            `json:"length"`
+	Code     DiagnosticCode `json:"code"`
+	Severity Severity       `json:"severity"`
+	Message  string         `json:"message"`
+}
+
+//newDiagnostic creates an error diagnostic. The location is set by the caller.
+func newDiagnostic(code DiagnosticCode, format string, a ...interface{}) *Diagnostic {
+	return &Diagnostic{Code: code, Severity: SeverityError, Message: fmt.Sprintf(format, a...)}
+}
+
+//at sets the location of the offending element in the line and returns the diagnostic
+func (diagnostic Diagnostic) at(column, length int) Diagnostic {
+	diagnostic.Column = column
+	diagnostic.Length = length
+	return diagnostic
+}
+
+//String formats the diagnostic for display
+func (diagnostic Diagnostic) String() string {
+	if diagnostic.Line == 0 {
+		return fmt.Sprintf("%s: %s", diagnostic.Severity, diagnostic.Message)
+	}
+	if diagnostic.Column == 0 {
+		return fmt.Sprintf("%s at line %d: %s", diagnostic.Severity, diagnostic.Line, diagnostic.Message)
+	}
+	return fmt.Sprintf("%s at line %d, column %d: %s", diagnostic.Severity, diagnostic.Line, diagnostic.Column, diagnostic.Message)
+}
+
+//HasErrors returns true if one of the diagnostics is an error (or worse)
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity >= SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnostic_String(t *testing.T) {
+	tests := []struct {
+		name       string
+		diagnostic Diagnostic
+		want       string
+	}{
+		{"Line error", Diagnostic{Line: 12, Severity: SeverityError, Message: "Attempt to redefine MyCall"}, "Error at line 12: Attempt to redefine MyCall"},
+		{"Line warning", Diagnostic{Line: 3, Severity: SeverityWarning, Message: "something odd"}, "Warning at line 3: something odd"},
+		{"Global fatal error", Diagnostic{Severity: SeverityFatal, Message: "missing new time to infer time"}, "Fatal error: missing new time to infer time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diagnostic.String(); got != tt.want {
+				t.Errorf("Diagnostic.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagnostics []Diagnostic
+		want        bool
+	}{
+		{"No diagnostic", nil, false},
+		{"Only warnings", []Diagnostic{{Severity: SeverityWarning}}, false},
+		{"Error", []Diagnostic{{Severity: SeverityWarning}, {Severity: SeverityError}}, true},
+		{"Fatal error", []Diagnostic{{Severity: SeverityFatal}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasErrors(tt.diagnostics); got != tt.want {
+				t.Errorf("HasErrors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+//diagnosticMessage returns the message of the diagnostic or an empty string if there is none
+func diagnosticMessage(diagnostic *Diagnostic) string {
+	if diagnostic == nil {
+		return ""
+	}
+	return diagnostic.Message
+}
+
+//diagnosticMessages returns the messages of the diagnostics as a comma separated list
+func diagnosticMessages(diagnostics []Diagnostic) string {
+	var messages []string
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, diagnostic.Message)
+	}
+	return strings.Join(messages, ", ")
+}
@@ -29,6 +29,7 @@ func SprintLogRecord(logLine LogLine) string {
 	output.WriteString("Operator  " + logLine.Operator + "\n")
 	output.WriteString("MyWWFF    " + logLine.MyWWFF + "\n")
 	output.WriteString("MySOTA    " + logLine.MySOTA + "\n")
+	output.WriteString("MyPOTA    " + logLine.MyPOTA + "\n")
 	output.WriteString("MyGrid    " + logLine.MyGrid + "\n")
 	output.WriteString("QslMsg    " + logLine.QslMsgFromHeader + "\n")
 	output.WriteString("Nickname  " + logLine.Nickname + "\n")
@@ -48,6 +49,11 @@ func SprintLogRecord(logLine LogLine) string {
 	output.WriteString("RSTrcvd   " + logLine.RSTrcvd + "\n")
 	output.WriteString("SOTA      " + logLine.SOTA + "\n")
 	output.WriteString("WWFF      " + logLine.WWFF + "\n")
+	output.WriteString("POTA      " + logLine.POTA + "\n")
+	output.WriteString("STX       " + logLine.STX + "\n")
+	output.WriteString("SRX       " + logLine.SRX + "\n")
+	output.WriteString("STXstring " + logLine.STXstring + "\n")
+	output.WriteString("SRXstring " + logLine.SRXstring + "\n")
 
 	return output.String()
 }
@@ -56,22 +62,26 @@ func SprintLogRecord(logLine LogLine) string {
 func SprintHeaderValues(logLine LogLine) string {
 	var output strings.Builder
 
-	output.WriteString("MyCall    " + logLine.MyCall)
+	output.WriteString("MyCall    " + logLine.displayValue(FieldMyCall, logLine.MyCall))
 	if logLine.Operator != "" {
-		output.WriteString(" (" + logLine.Operator + ")")
+		output.WriteString(" (" + logLine.displayValue(FieldOperator, logLine.Operator) + ")")
 	}
 	output.WriteString("\n")
 
 	if logLine.MyWWFF != "" {
-		output.WriteString("MyWWFF    " + logLine.MyWWFF + "\n")
+		output.WriteString("MyWWFF    " + logLine.displayValue(FieldMyWWFF, logLine.MyWWFF) + "\n")
 	}
 
 	if logLine.MySOTA != "" {
-		output.WriteString("MySOTA    " + logLine.MySOTA + "\n")
+		output.WriteString("MySOTA    " + logLine.displayValue(FieldMySOTA, logLine.MySOTA) + "\n")
+	}
+
+	if logLine.MyPOTA != "" {
+		output.WriteString("MyPOTA    " + logLine.displayValue(FieldMyPOTA, logLine.MyPOTA) + "\n")
 	}
 
 	if logLine.MyGrid != "" {
-		output.WriteString("MyGrid    " + logLine.MyGrid + "\n")
+		output.WriteString("MyGrid    " + logLine.displayValue(FieldMyGrid, logLine.MyGrid) + "\n")
 	}
 
 	return output.String()
@@ -104,16 +114,37 @@ func SprintLogInColumn(logLine LogLine) (output string) {
 		notes.WriteString(logLine.OMname + " ")
 	}
 	if logLine.GridLoc != "" {
-		notes.WriteString(logLine.GridLoc + " ")
+		notes.WriteString(logLine.displayValue(FieldGridLoc, logLine.GridLoc) + " ")
 	}
 	if logLine.WWFF != "" {
 		notes.WriteString(logLine.WWFF + " ")
 	}
-	if logLine.SOTA != "" {
+	if isS2S(logLine) {
+		notes.WriteString("S2S " + logLine.SOTA + " ")
+	} else if logLine.SOTA != "" {
 		notes.WriteString(logLine.SOTA + " ")
 	}
+	if logLine.POTA != "" {
+		notes.WriteString(logLine.POTA + " ")
+	}
+	if sentExchange := sprintExchange(logLine.STX, logLine.STXstring); sentExchange != "" {
+		notes.WriteString("Sent: " + sentExchange + " ")
+	}
+	if rcvdExchange := sprintExchange(logLine.SRX, logLine.SRXstring); rcvdExchange != "" {
+		notes.WriteString("Rcvd: " + rcvdExchange + " ")
+	}
 
-	output = fmt.Sprintf(logLineFormat, logLine.Date, logLine.Time, logLine.Band, logLine.Mode, logLine.Call, logLine.RSTsent, logLine.RSTrcvd, notes.String())
+	//Invalid values are marked with a "*"
+	output = fmt.Sprintf(logLineFormat, logLine.displayValue(FieldDate, logLine.Date), logLine.Time, logLine.Band, logLine.Mode,
+		logLine.displayValue(FieldCall, logLine.Call), logLine.displayValue(FieldRSTsent, logLine.RSTsent), logLine.displayValue(FieldRSTrcvd, logLine.RSTrcvd), notes.String())
 
 	return output
 }
+
+// sprintExchange combines the serial number and the exchange string of a contest exchange
+func sprintExchange(serial, exchange string) string {
+	if serial != "" && exchange != "" {
+		return serial + " " + exchange
+	}
+	return serial + exchange
+}
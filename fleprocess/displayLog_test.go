@@ -40,6 +40,11 @@ func TestSprintHeaderValues(t *testing.T) {
 			args{logLine: LogLine{MyCall: "on4kjm/p"}},
 			"MyCall    on4kjm/p\n",
 		},
+		{
+			"Full Option with MyPOTA",
+			args{logLine: LogLine{MyCall: "on4kjm/p", Operator: "on4kjm", MyPOTA: "pota"}},
+			"MyCall    on4kjm/p (on4kjm)\nMyPOTA    pota\n",
+		},
 		{
 			"Full Option with MyGrid",
 			args{logLine: LogLine{MyCall: "on4kjm/p", Operator: "on4kjm", MyWWFF: "wwff", MySOTA: "sota", MyGrid: "grid"}},
@@ -70,6 +75,7 @@ func ExampleSprintLogRecord() {
 		Operator:         "operator",
 		MyWWFF:           "myWwff",
 		MySOTA:           "mySota",
+		MyPOTA:           "myPota",
 		MyGrid:           "myGrid",
 		QslMsgFromHeader: "QslMsgFromHeader",
 		Nickname:         "nickname",
@@ -89,6 +95,11 @@ func ExampleSprintLogRecord() {
 		RSTrcvd:          "rstRcvd",
 		SOTA:             "sota",
 		WWFF:             "wwff",
+		POTA:             "pota",
+		STX:              "stx",
+		SRX:              "srx",
+		STXstring:        "stxString",
+		SRXstring:        "srxString",
 	}
 	out := SprintLogRecord(logLine)
 	fmt.Print(out)
@@ -99,6 +110,7 @@ func ExampleSprintLogRecord() {
 	//Operator  operator
 	//MyWWFF    myWwff
 	//MySOTA    mySota
+	//MyPOTA    myPota
 	//MyGrid    myGrid
 	//QslMsg    QslMsgFromHeader
 	//Nickname  nickname
@@ -118,6 +130,11 @@ func ExampleSprintLogRecord() {
 	//RSTrcvd   rstRcvd
 	//SOTA      sota
 	//WWFF      wwff
+	//POTA      pota
+	//STX       stx
+	//SRX       srx
+	//STXstring stxString
+	//SRXstring srxString
 
 }
 
@@ -158,7 +175,7 @@ func TestSprintLogInColumn(t *testing.T) {
 				SOTA:             "sota",
 				WWFF:             "wwff"},
 			},
-			"date       time band mode call         rstSent rstRcvd QRG: frequency [comment] [qslMessage] omName gridLoc wwff sota \n",
+			"date       time band mode call         rstSent rstRcvd QRG: frequency [comment] [qslMessage] omName gridLoc wwff S2S sota \n",
 		},
 		{
 			"Minimal",
@@ -182,6 +199,54 @@ func TestSprintLogInColumn(t *testing.T) {
 			},
 			"date       time band mode call         rstSent rstRcvd \n",
 		},
+		{
+			"Contest exchange",
+			args{logLine: LogLine{
+				Date:      "date",
+				MyCall:    "myCall",
+				Mode:      "mode",
+				Band:      "band",
+				Time:      "time",
+				Call:      "call",
+				RSTsent:   "rstSent",
+				RSTrcvd:   "rstRcvd",
+				STX:       "33",
+				STXstring: "JN58",
+				SRXstring: "WY"},
+			},
+			"date       time band mode call         rstSent rstRcvd Sent: 33 JN58 Rcvd: WY \n",
+		},
+		{
+			"SOTA chaser (not S2S)",
+			args{logLine: LogLine{
+				Date:    "date",
+				MyCall:  "myCall",
+				Mode:    "mode",
+				Band:    "band",
+				Time:    "time",
+				Call:    "call",
+				RSTsent: "rstSent",
+				RSTrcvd: "rstRcvd",
+				SOTA:    "sota"},
+			},
+			"date       time band mode call         rstSent rstRcvd sota \n",
+		},
+		{
+			"Invalid values",
+			args{logLine: LogLine{
+				Date:          "2020-13-01",
+				MyCall:        "myCall",
+				Mode:          "FM",
+				Band:          "band",
+				Time:          "time",
+				Call:          "FOOBAR",
+				RSTsent:       "59",
+				RSTrcvd:       "599",
+				GridLoc:       "grid",
+				InvalidFields: FieldSet(0).with(FieldDate, true).with(FieldCall, true).with(FieldRSTrcvd, true).with(FieldGridLoc, true)},
+			},
+			"*2020-13-01 time band FM   *FOOBAR      59   *599 *grid \n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
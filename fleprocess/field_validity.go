@@ -0,0 +1,136 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+//LogField identifies a LogLine field that is validated while loading the log
+type LogField uint
+
+//Validated LogLine fields
+const (
+	FieldDate LogField = iota
+	FieldMyCall
+	FieldOperator
+	FieldMyWWFF
+	FieldMySOTA
+	FieldMyPOTA
+	FieldMyGrid
+	FieldCall
+	FieldGridLoc
+	FieldRSTsent
+	FieldRSTrcvd
+)
+
+//logFieldNames are the field names, as used in the JSON output
+var logFieldNames = []string{"date", "myCall", "operator", "myWwff", "mySota", "myPota", "myGrid", "call", "gridLoc", "rstSent", "rstRcvd"}
+
+//headerFields are the fields defined by a header keyword
+var headerFields = []LogField{FieldMyCall, FieldOperator, FieldMyWWFF, FieldMySOTA, FieldMyPOTA, FieldMyGrid}
+
+//qsoFields are the fields specific to a QSO (they are not carried over to the next log line)
+var qsoFields = []LogField{FieldCall, FieldGridLoc, FieldRSTsent, FieldRSTrcvd}
+
+//String returns the name of the field
+func (field LogField) String() string {
+	if int(field) < len(logFieldNames) {
+		return logFieldNames[field]
+	}
+	return fmt.Sprintf("LogField(%d)", int(field))
+}
+
+//FieldSet is a set of LogLine fields.
+//Being a plain value, it is copied along with the LogLine holding it.
+type FieldSet uint32
+
+//Contains tells whether the field is part of the set
+func (set FieldSet) Contains(field LogField) bool {
+	return set&(1<<field) != 0
+}
+
+//with returns the set with the field added (isIncluded true) or removed
+func (set FieldSet) with(field LogField, isIncluded bool) FieldSet {
+	if isIncluded {
+		return set | 1<<field
+	}
+	return set &^ (1 << field)
+}
+
+//Fields lists the fields of the set in their definition order
+func (set FieldSet) Fields() (fields []LogField) {
+	for field := range logFieldNames {
+		if set.Contains(LogField(field)) {
+			fields = append(fields, LogField(field))
+		}
+	}
+	return fields
+}
+
+//MarshalJSON outputs the set as a list of field names
+func (set FieldSet) MarshalJSON() ([]byte, error) {
+	names := []string{}
+	for _, field := range set.Fields() {
+		names = append(names, field.String())
+	}
+	return json.Marshal(names)
+}
+
+//IsValid returns false if the value of the field was found invalid while loading the log
+func (logLine LogLine) IsValid(field LogField) bool {
+	return !logLine.InvalidFields.Contains(field)
+}
+
+//setValidity records whether the value of the field is valid, based on the result of its validation
+func (logLine *LogLine) setValidity(field LogField, diagnostic *Diagnostic) {
+	logLine.InvalidFields = logLine.InvalidFields.with(field, diagnostic != nil)
+}
+
+//firstInvalidField returns the first of the supplied fields holding an invalid value
+func firstInvalidField(logLine LogLine, fields []LogField) (field LogField, isInvalid bool) {
+	for _, field := range fields {
+		if !logLine.IsValid(field) {
+			return field, true
+		}
+	}
+	return 0, false
+}
+
+//invalidLineFields lists the invalid fields set by the log line itself (header fields are left out)
+func invalidLineFields(logLine LogLine) (fields []LogField) {
+	for _, field := range append([]LogField{FieldDate}, qsoFields...) {
+		if !logLine.IsValid(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+//isExportable tells whether the fields identifying a QSO (date, calls) are valid
+func (logLine LogLine) isExportable() bool {
+	return logLine.IsValid(FieldDate) && logLine.IsValid(FieldMyCall) && logLine.IsValid(FieldCall)
+}
+
+//displayValue returns the value of a field for display. Invalid values are marked with a leading "*".
+func (logLine LogLine) displayValue(field LogField, value string) string {
+	if value != "" && !logLine.IsValid(field) {
+		return "*" + value
+	}
+	return value
+}
@@ -0,0 +1,99 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFieldSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		set        FieldSet
+		wantFields []LogField
+		wantJSON   string
+	}{
+		{"Empty set", FieldSet(0), nil, "[]"},
+		{"Single field", FieldSet(0).with(FieldCall, true), []LogField{FieldCall}, `["call"]`},
+		{"Several fields", FieldSet(0).with(FieldRSTrcvd, true).with(FieldDate, true).with(FieldMyGrid, true), []LogField{FieldDate, FieldMyGrid, FieldRSTrcvd}, `["date","myGrid","rstRcvd"]`},
+		{"Removed field", FieldSet(0).with(FieldCall, true).with(FieldDate, true).with(FieldCall, false), []LogField{FieldDate}, `["date"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotFields := tt.set.Fields(); !reflect.DeepEqual(gotFields, tt.wantFields) {
+				t.Errorf("FieldSet.Fields() = %v, want %v", gotFields, tt.wantFields)
+			}
+			gotJSON, err := json.Marshal(tt.set)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if string(gotJSON) != tt.wantJSON {
+				t.Errorf("json.Marshal(FieldSet) = %s, want %s", gotJSON, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestLogLine_setValidity(t *testing.T) {
+	logLine := LogLine{Call: "FOOBAR"}
+	logLine.setValidity(FieldCall, newDiagnostic(CodeInvalidCall, "[FOOBAR] is an invalid call"))
+	if logLine.IsValid(FieldCall) {
+		t.Error("The call should be flagged invalid")
+	}
+	if !logLine.IsValid(FieldDate) {
+		t.Error("The date should not be flagged invalid")
+	}
+	if logLine.Call != "FOOBAR" {
+		t.Errorf("The invalid value should be kept as is, got %s", logLine.Call)
+	}
+
+	logLine.setValidity(FieldCall, nil)
+	if !logLine.IsValid(FieldCall) {
+		t.Error("The call should be valid again")
+	}
+}
+
+func Test_invalidLineFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		logLine LogLine
+		want    []LogField
+	}{
+		{"All valid", LogLine{}, nil},
+		{"Header fields are left out", LogLine{InvalidFields: FieldSet(0).with(FieldMyCall, true).with(FieldMySOTA, true)}, nil},
+		{"Line fields", LogLine{InvalidFields: FieldSet(0).with(FieldMyCall, true).with(FieldGridLoc, true).with(FieldDate, true)}, []LogField{FieldDate, FieldGridLoc}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalidLineFields(tt.logLine); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invalidLineFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogLine_displayValue(t *testing.T) {
+	logLine := LogLine{Call: "FOOBAR", RSTsent: "599", InvalidFields: FieldSet(0).with(FieldCall, true)}
+	if got := logLine.displayValue(FieldCall, logLine.Call); got != "*FOOBAR" {
+		t.Errorf("displayValue() = %s, want *FOOBAR", got)
+	}
+	if got := logLine.displayValue(FieldRSTsent, logLine.RSTsent); got != "599" {
+		t.Errorf("displayValue() = %s, want 599", got)
+	}
+}
@@ -0,0 +1,304 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//FmtParams is holding the parameters of the fmt command
+type FmtParams struct {
+	InputFilenames []string
+	//IsCheck only reports the files that are not formatted
+	IsCheck bool
+}
+
+//ProcessFmtCommand rewrites the FLE files in their canonical form. It is called from the COBRA interface.
+//In check mode, the files are not modified: the ones that are not formatted are listed and an error is returned.
+func ProcessFmtCommand(fmtParams FmtParams) error {
+	if len(fmtParams.InputFilenames) == 0 {
+		return fmt.Errorf("No input file")
+	}
+
+	var failedFiles, unformattedFiles []string
+	for _, inputFilename := range fmtParams.InputFilenames {
+		input, err := ioutil.ReadFile(inputFilename)
+		if err != nil {
+			fmt.Println(err)
+			failedFiles = append(failedFiles, inputFilename)
+			continue
+		}
+
+		formatted, diagnostics := FormatFle(string(input))
+		if HasErrors(diagnostics) {
+			fmt.Printf("%s can't be formatted:\n", inputFilename)
+			for _, diagnostic := range diagnostics {
+				fmt.Println(diagnostic)
+			}
+			failedFiles = append(failedFiles, inputFilename)
+			continue
+		}
+		if formatted == string(input) {
+			continue
+		}
+
+		if fmtParams.IsCheck {
+			fmt.Println(inputFilename)
+			unformattedFiles = append(unformattedFiles, inputFilename)
+			continue
+		}
+		fileInfo, err := os.Stat(inputFilename)
+		if err == nil {
+			err = ioutil.WriteFile(inputFilename, []byte(formatted), fileInfo.Mode())
+		}
+		if err != nil {
+			fmt.Println(err)
+			failedFiles = append(failedFiles, inputFilename)
+		}
+	}
+
+	if len(failedFiles) != 0 {
+		return fmt.Errorf("Could not format %s", strings.Join(failedFiles, ", "))
+	}
+	if len(unformattedFiles) != 0 {
+		return fmt.Errorf("%d file(s) not formatted", len(unformattedFiles))
+	}
+	return nil
+}
+
+//FormatFle returns the canonical form of a FLE log:
+//  - header keywords in lowercase followed by their normalized value,
+//  - context elements (date, band, frequency, mode) on their own line, in that order,
+//  - QSO lines with full times, uppercase calls and references, the time, call and reports being aligned in columns.
+//The comments, multi-line comment blocks and empty lines are kept as they are. The header keywords without value are dropped.
+//A log containing errors is not formatted: the errors are returned as diagnostics.
+func FormatFle(input string) (formatted string, diagnostics []Diagnostic) {
+	if _, diagnostics = LoadLog(strings.NewReader(input), false); HasErrors(diagnostics) {
+		return "", diagnostics
+	}
+
+	statements, _, err := ParseFle(strings.NewReader(input))
+	if err != nil {
+		return "", fileAccessDiagnostics("failed reading input", err)
+	}
+
+	//The QSO lines are aligned once the width of their columns is known
+	var lines []formattedLine
+	var columnWidths [qsoColumnCount]int
+	var previousLogLine LogLine
+	for _, statement := range statements {
+		switch typedStatement := statement.(type) {
+		case CommentStatement:
+			if regexpOnlySpaces.MatchString(typedStatement.Text) {
+				lines = append(lines, formattedLine{text: ""})
+			} else {
+				lines = append(lines, formattedLine{text: typedStatement.Text})
+			}
+		case HeaderStatement:
+			//A header keyword without value has no effect
+			if value := formatHeaderValue(typedStatement); value != "" {
+				lines = append(lines, formattedLine{text: typedStatement.Keyword + " " + value})
+			}
+		case ContextStatement:
+			logLine, _ := evaluateDataLine(typedStatement.DataLine, nil, previousLogLine)
+			context := formatContext(typedStatement.DataLine, logLine)
+			context = append(context, formatQsoDetails(typedStatement.DataLine, logLine)...)
+			if len(context) > 0 {
+				lines = append(lines, formattedLine{text: strings.Join(context, " ")})
+			}
+			previousLogLine = logLine
+		case QsoStatement:
+			logLine, _ := evaluateDataLine(typedStatement.DataLine, nil, previousLogLine)
+			if context := formatContext(typedStatement.DataLine, logLine); len(context) > 0 {
+				lines = append(lines, formattedLine{text: strings.Join(context, " ")})
+			}
+			qsoLine := formattedLine{isQso: true, columns: formatQsoColumns(typedStatement.DataLine, logLine)}
+			qsoLine.text = strings.Join(formatQsoDetails(typedStatement.DataLine, logLine), " ")
+			for i, column := range qsoLine.columns {
+				if len(column) > columnWidths[i] {
+					columnWidths[i] = len(column)
+				}
+			}
+			lines = append(lines, qsoLine)
+			previousLogLine = logLine
+		}
+	}
+
+	var output strings.Builder
+	for _, line := range lines {
+		output.WriteString(line.String(columnWidths))
+		output.WriteString("\n")
+	}
+	return output.String(), diagnostics
+}
+
+//Columns of the formatted QSO lines
+const (
+	qsoColumnTime = iota
+	qsoColumnCall
+	qsoColumnSentReport
+	qsoColumnRcvdReport
+	qsoColumnCount
+)
+
+//formattedLine is a line of the formatted log. The QSO lines have aligned columns, followed by the other QSO details (text).
+type formattedLine struct {
+	isQso   bool
+	columns [qsoColumnCount]string
+	text    string
+}
+
+//String returns the formatted line, its columns being padded to the supplied widths
+func (line formattedLine) String(columnWidths [qsoColumnCount]int) string {
+	if !line.isQso {
+		return line.text
+	}
+	var output strings.Builder
+	for i, column := range line.columns {
+		output.WriteString(fmt.Sprintf("%-*s ", columnWidths[i], column))
+	}
+	output.WriteString(line.text)
+	return strings.TrimRight(output.String(), " ")
+}
+
+//formatHeaderValue returns the normalized value of a header statement
+func formatHeaderValue(header HeaderStatement) string {
+	value := strings.TrimSpace(header.Value.Text)
+	switch header.Keyword {
+	case "mycall", "operator":
+		value, _ = ValidateCall(value)
+	case "mywwff":
+		value, _ = ValidateWwffList(value)
+	case "mysota":
+		value, _ = ValidateSota(value)
+	case "mypota":
+		value, _ = ValidatePotaList(value)
+	case "mygrid":
+		value, _ = ValidateGridLocator(value)
+	case "region":
+		value, _ = ValidateRegion(value)
+	case "qslmsg":
+		//The QSL message is kept as it is
+		if value != "" {
+			value = header.Value.Text
+		}
+	case "serial":
+		if serialNumbers, errorMsg := parseSerialDefinition(value); errorMsg == "" {
+			value = strconv.Itoa(serialNumbers.start)
+			if serialNumbers.isPerBand {
+				value += " band"
+			}
+		}
+	}
+	return value
+}
+
+//hasElement returns true if the data line contains an element of the given type
+func hasElement(dataLine DataLine, tokenType TokenType) bool {
+	for _, element := range dataLine.Elements {
+		if element.Type == tokenType {
+			return true
+		}
+	}
+	return false
+}
+
+//formatContext returns the context elements of a data line (date, band, frequency, mode and the time of a line without call)
+func formatContext(dataLine DataLine, logLine LogLine) (context []string) {
+	dayIncrement := 0
+	for _, element := range dataLine.Elements {
+		if element.Type == TokenDayIncrement {
+			dayIncrement += len(element.Text)
+		}
+	}
+	//An explicit date includes the day increments of the line
+	if hasElement(dataLine, TokenDate) {
+		context = append(context, "date "+logLine.Date)
+	} else if dayIncrement > 0 {
+		context = append(context, "day "+strings.Repeat("+", dayIncrement))
+	}
+	if hasElement(dataLine, TokenBand) {
+		context = append(context, logLine.Band)
+	}
+	//A frequency that can't be used (e.g. outside of the amateur bands) is kept as it was entered
+	for _, element := range dataLine.Elements {
+		if element.Type == TokenFrequency {
+			if logLine.Frequency != "" {
+				context = append(context, logLine.Frequency)
+			} else {
+				context = append(context, element.Text)
+			}
+		}
+	}
+	if hasElement(dataLine, TokenMode) {
+		context = append(context, strings.ToLower(logLine.Mode))
+	}
+	if logLine.Call == "" && hasElement(dataLine, TokenTime) {
+		context = append(context, logLine.ActualTime)
+	}
+	return context
+}
+
+//formatQsoColumns returns the aligned columns of a QSO line: time, call and reports
+func formatQsoColumns(dataLine DataLine, logLine LogLine) (columns [qsoColumnCount]string) {
+	columns[qsoColumnTime] = logLine.ActualTime
+	columns[qsoColumnCall] = logLine.Call
+	//The reports are only written if they were in the input. The received report is positional: it requires the sent one.
+	if hasElement(dataLine, TokenSentReport) && isReport(logLine.RSTsent) {
+		columns[qsoColumnSentReport] = logLine.RSTsent
+		if hasElement(dataLine, TokenRcvdReport) && isReport(logLine.RSTrcvd) {
+			columns[qsoColumnRcvdReport] = logLine.RSTrcvd
+		}
+	}
+	return columns
+}
+
+//formatQsoDetails returns the other elements of a data line: contest exchanges, references, name, grid, comment and QSL message.
+//The optional wwff, sota and pota keywords are dropped.
+func formatQsoDetails(dataLine DataLine, logLine LogLine) (details []string) {
+	for _, element := range dataLine.Elements {
+		switch element.Type {
+		case TokenSentExchange, TokenRcvdExchange:
+			serial, exchange := splitContestExchange(element.Text[1:])
+			details = append(details, element.Text[:1]+serial+exchange)
+		}
+	}
+	if hasElement(dataLine, TokenWwffRef) {
+		details = append(details, logLine.WWFF)
+	}
+	if hasElement(dataLine, TokenSotaRef) {
+		details = append(details, logLine.SOTA)
+	}
+	if hasElement(dataLine, TokenPotaRef) {
+		details = append(details, logLine.POTA)
+	}
+	for _, element := range dataLine.Elements {
+		switch element.Type {
+		case TokenName:
+			details = append(details, "@"+logLine.OMname)
+		case TokenGrid:
+			details = append(details, "#"+logLine.GridLoc)
+		case TokenComment, TokenQslMessage:
+			details = append(details, element.Source())
+		}
+	}
+	return details
+}
@@ -0,0 +1,168 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatFle(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantFormatted string
+		wantErr       bool
+	}{
+		{
+			"Canonical form",
+			"# Header\n" +
+				"MyCall  on4kjm/p\n" +
+				"mywwff onff-0258\n" +
+				"region  1 \n" +
+				"operator \n" +
+				"{ multi-line\n" +
+				"   comment }\n" +
+				"   \n" +
+				"date 2020-5-23 40m cw\n" +
+				"1310 ik5zve 5 <tnx>\n" +
+				"2 dl/on4kjm 55 57 wwff dlff-0001 @Jean #jo40eu [qsl]\n" +
+				"20m ssb 1320 s57lc\n" +
+				"day ++ 14.250 g4elz ,012 .ab\n",
+			"# Header\n" +
+				"mycall ON4KJM/P\n" +
+				"mywwff ONFF-0258\n" +
+				"region 1\n" +
+				"{ multi-line\n" +
+				"   comment }\n" +
+				"\n" +
+				"date 2020-05-23 40m cw\n" +
+				"1310 IK5ZVE    559     <tnx>\n" +
+				"1312 DL/ON4KJM 559 579 DLFF-0001 @Jean #JO40eu [qsl]\n" +
+				"20m ssb\n" +
+				"1320 S57LC\n" +
+				"day ++ 14.250\n" +
+				"     G4ELZ             ,12 .AB\n",
+			false,
+		},
+		{
+			"Frequencies normalized in MHz",
+			"mycall on4kjm/p\n" +
+				"date 2020-05-23 40m cw\n" +
+				"7018 1310 ik5zve\n" +
+				"20m 14062k 1315 dl1aa\n",
+			"mycall ON4KJM/P\n" +
+				"date 2020-05-23 40m cw\n" +
+				"7.018\n" +
+				"1310 IK5ZVE\n" +
+				"20m 14.062\n" +
+				"1315 DL1AA\n",
+			false,
+		},
+		{
+			"Frequency outside of the amateur bands kept",
+			"mycall on4kjm/p\n" +
+				"date 2020-05-23 cw\n" +
+				"99.5 1310 ik5zve\n",
+			"mycall ON4KJM/P\n" +
+				"date 2020-05-23 cw\n" +
+				"99.5\n" +
+				"1310 IK5ZVE\n",
+			false,
+		},
+		{
+			"Log with errors",
+			"mycall on4kjm/p\n" +
+				"date 2020-05-23 40m cw\n" +
+				"1310 ik5zve foo\n",
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFormatted, gotDiagnostics := FormatFle(tt.input)
+			if HasErrors(gotDiagnostics) != tt.wantErr {
+				t.Fatalf("FormatFle() diagnostics = %v, wantErr %v", gotDiagnostics, tt.wantErr)
+			}
+			if gotFormatted != tt.wantFormatted {
+				t.Errorf("FormatFle() = \n%v, want \n%v", gotFormatted, tt.wantFormatted)
+			}
+		})
+	}
+}
+
+//The formatted sample logs must hold the same QSOs and be stable
+func TestFormatFle_samples(t *testing.T) {
+	sampleFilenames, _ := filepath.Glob("../test/data/*.txt")
+	for _, sampleFilename := range sampleFilenames {
+		t.Run(filepath.Base(sampleFilename), func(t *testing.T) {
+			input, _ := ioutil.ReadFile(sampleFilename)
+			formatted, diagnostics := FormatFle(string(input))
+			if HasErrors(diagnostics) {
+				return
+			}
+
+			inputLog, _ := LoadLog(strings.NewReader(string(input)), true)
+			formattedLog, _ := LoadLog(strings.NewReader(formatted), true)
+			if !reflect.DeepEqual(inputLog, formattedLog) {
+				t.Errorf("FormatFle() changed the log: %v, want %v", formattedLog, inputLog)
+			}
+			if reformatted, _ := FormatFle(formatted); reformatted != formatted {
+				t.Errorf("FormatFle() is not stable: %v, want %v", reformatted, formatted)
+			}
+		})
+	}
+}
+
+func TestProcessFmtCommand(t *testing.T) {
+	directory, err := ioutil.TempDir("", "FLEcli-fmt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	formattedFilename := filepath.Join(directory, "formatted.txt")
+	ioutil.WriteFile(formattedFilename, []byte("mycall ON4KJM/P\ndate 2020-05-23 40m cw\n1310 IK5ZVE\n"), 0644)
+	unformattedFilename := filepath.Join(directory, "unformatted.txt")
+	ioutil.WriteFile(unformattedFilename, []byte("mycall on4kjm/p\ndate 2020-05-23 40m cw\n1310 ik5zve\n"), 0644)
+	errorFilename := filepath.Join(directory, "error.txt")
+	ioutil.WriteFile(errorFilename, []byte("mycall on4kjm/p\n1310 ik5zve foo\n"), 0644)
+
+	if err := ProcessFmtCommand(FmtParams{InputFilenames: []string{formattedFilename}, IsCheck: true}); err != nil {
+		t.Errorf("ProcessFmtCommand() unexpected error for a formatted file: %v", err)
+	}
+	if err := ProcessFmtCommand(FmtParams{InputFilenames: []string{formattedFilename, unformattedFilename}, IsCheck: true}); err == nil {
+		t.Errorf("ProcessFmtCommand() expected an error for an unformatted file")
+	}
+	if err := ProcessFmtCommand(FmtParams{InputFilenames: []string{errorFilename}}); err == nil {
+		t.Errorf("ProcessFmtCommand() expected an error for a log with errors")
+	}
+	if err := ProcessFmtCommand(FmtParams{InputFilenames: []string{unformattedFilename}}); err != nil {
+		t.Errorf("ProcessFmtCommand() unexpected error: %v", err)
+	}
+	if formatted, _ := ioutil.ReadFile(unformattedFilename); string(formatted) != "mycall ON4KJM/P\ndate 2020-05-23 40m cw\n1310 IK5ZVE\n" {
+		t.Errorf("ProcessFmtCommand() wrote %v", string(formatted))
+	}
+	if err := ProcessFmtCommand(FmtParams{}); err == nil {
+		t.Errorf("ProcessFmtCommand() expected an error without input file")
+	}
+}
@@ -0,0 +1,261 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//FromAdifParams is holding the parameters required to convert an ADIF file to a FLE file
+type FromAdifParams struct {
+	InputFilename  string
+	OutputFilename string
+	IsOverwrite    bool
+}
+
+//ProcessFromAdifCommand converts an ADIF file to a FLE file that can be corrected by hand. It is called from the COBRA interface.
+//The values that can't be expressed in FLE are listed as warnings.
+func ProcessFromAdifCommand(fromAdifParams FromAdifParams) error {
+	verifiedOutputFilename, err := buildOutputFilename(fromAdifParams.OutputFilename, fromAdifParams.InputFilename, fromAdifParams.IsOverwrite, ".txt")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(fromAdifParams.InputFilename)
+	if err != nil {
+		return fmt.Errorf("failed opening file: %s", err)
+	}
+	defer file.Close()
+
+	adifFile, err := ReadAdif(file)
+	if err != nil {
+		return fmt.Errorf("Invalid ADIF file: %s", err)
+	}
+	records := adifFile.Records
+	if len(records) == 0 {
+		return fmt.Errorf("No QSO found")
+	}
+
+	fleLog, warnings := buildFleFromAdif(records, filepath.Base(fromAdifParams.InputFilename))
+	for _, warning := range warnings {
+		fmt.Println("Warning: " + warning)
+	}
+
+	//The generated log is aligned if it can be loaded without error. Otherwise the problems are listed to be corrected by hand.
+	formattedLog, diagnostics := FormatFle(fleLog)
+	if HasErrors(diagnostics) {
+		fmt.Println("\nThe generated FLE file contains errors:")
+		for _, diagnostic := range diagnostics {
+			fmt.Println(diagnostic)
+		}
+		formattedLog = fleLog
+	}
+
+	if err := ioutil.WriteFile(verifiedOutputFilename, []byte(formattedLog), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("\nSuccessfully converted %d QSOs to file \"%s\"\n", len(records), verifiedOutputFilename)
+	return nil
+}
+
+//fleHeader holds the FLE header values of an ADIF record
+type fleHeader struct {
+	myCall   string
+	operator string
+	myWwff   string
+	mySota   string
+	myPota   string
+	myGrid   string
+	nickname string
+}
+
+//keywords returns the header keywords and their value, in the order they are written
+func (header fleHeader) keywords() [][2]string {
+	return [][2]string{
+		{"mycall", header.myCall},
+		{"operator", header.operator},
+		{"mywwff", header.myWwff},
+		{"mysota", header.mySota},
+		{"mypota", header.myPota},
+		{"mygrid", header.myGrid},
+		{"nickname", header.nickname},
+	}
+}
+
+//adifSigReference returns the reference of a special interest activity, given as a dedicated field
+//(ADIF 3.1.4) or as a SIG/SIG_INFO pair (as generated before ADIF 3.1.4)
+func adifSigReference(record AdifRecord, refField, sigField, sigInfoField, sig string) string {
+	if record.Get(refField) != "" {
+		return record.Get(refField)
+	}
+	if strings.EqualFold(record.Get(sigField), sig) {
+		return record.Get(sigInfoField)
+	}
+	return ""
+}
+
+//buildFleFromAdif converts the ADIF records to a FLE log. The header values are written before the first QSO and when
+//they change, the date, band, mode and frequency only when they change.
+func buildFleFromAdif(records []AdifRecord, inputFilename string) (fleLog string, warnings []string) {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("# Converted from %s\n\n", inputFilename))
+
+	var previousHeader fleHeader
+	previousDate, previousBand, previousMode, previousFrequency := "", "", "", ""
+
+	for i, record := range records {
+		qsoLocation := fmt.Sprintf("QSO #%d (%s)", i+1, record.Get("CALL"))
+
+		//Header values
+		header := fleHeader{
+			myCall:   record.Get("STATION_CALLSIGN"),
+			operator: record.Get("OPERATOR"),
+			myWwff:   adifSigReference(record, "MY_WWFF_REF", "MY_SIG", "MY_SIG_INFO", "WWFF"),
+			mySota:   record.Get("MY_SOTA_REF"),
+			myPota:   adifSigReference(record, "MY_POTA_REF", "MY_SIG", "MY_SIG_INFO", "POTA"),
+			myGrid:   record.Get("MY_GRIDSQUARE"),
+			nickname: record.Get("APP_EQSL_QTH_NICKNAME"),
+		}
+		if header.myCall == "" {
+			header.myCall = record.Get("OWNER_CALLSIGN")
+		}
+		if i == 0 && header.myCall == "" {
+			warnings = append(warnings, fmt.Sprintf("%s has no station call sign: mycall must be added", qsoLocation))
+		}
+		previousKeywords := previousHeader.keywords()
+		for k, keyword := range header.keywords() {
+			if keyword[1] == previousKeywords[k][1] {
+				continue
+			}
+			if keyword[1] == "" {
+				//a FLE header value can't be removed
+				warnings = append(warnings, fmt.Sprintf("%s has no %s: the previous value is used", qsoLocation, keyword[0]))
+				continue
+			}
+			output.WriteString(fmt.Sprintf("%s %s\n", keyword[0], keyword[1]))
+		}
+		previousHeader = header
+		if i == 0 {
+			output.WriteString("\n")
+		}
+
+		//Context values
+		var context []string
+		if date := record.Get("QSO_DATE"); date != previousDate {
+			if len(date) == 8 {
+				context = append(context, fmt.Sprintf("date %s-%s-%s", date[0:4], date[4:6], date[6:8]))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s has an invalid date [%s]", qsoLocation, date))
+			}
+			previousDate = date
+		}
+		if band := strings.ToLower(record.Get("BAND")); band != previousBand {
+			if isKnownBand, _, _, _ := IsBand(band); isKnownBand {
+				context = append(context, band)
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s has an unsupported band [%s]", qsoLocation, record.Get("BAND")))
+			}
+			previousBand = band
+		}
+		if frequency := record.Get("FREQ"); frequency != previousFrequency {
+			if frequency == "" {
+				warnings = append(warnings, fmt.Sprintf("%s has no frequency: the previous value is used", qsoLocation))
+			} else {
+				context = append(context, frequency)
+			}
+			previousFrequency = frequency
+		}
+		//The FLE mode can be an ADIF submode (FT4 is a submode of MFSK)
+		mode := strings.ToUpper(record.Get("SUBMODE"))
+		if !lookupMode(mode) {
+			if mode != "" {
+				warnings = append(warnings, fmt.Sprintf("%s has an unsupported submode [%s]: the mode is used", qsoLocation, record.Get("SUBMODE")))
+			}
+			mode = strings.ToUpper(record.Get("MODE"))
+		}
+		if mode != previousMode {
+			if lookupMode(mode) {
+				context = append(context, strings.ToLower(mode))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s has an unsupported mode [%s]", qsoLocation, record.Get("MODE")))
+			}
+			previousMode = mode
+		}
+		if len(context) > 0 {
+			output.WriteString(strings.Join(context, " ") + "\n")
+		}
+
+		//QSO values
+		var qso []string
+		if timeOn := record.Get("TIME_ON"); len(timeOn) >= 4 {
+			qso = append(qso, timeOn[:4])
+		}
+		qso = append(qso, record.Get("CALL"))
+		_, defaultReport := getDefaultReport(mode)
+		rstSent, rstRcvd := record.Get("RST_SENT"), record.Get("RST_RCVD")
+		if (rstSent != "" && rstSent != defaultReport) || (rstRcvd != "" && rstRcvd != defaultReport) {
+			if rstSent == "" {
+				rstSent = defaultReport
+			}
+			if isReport(rstSent) && (rstRcvd == "" || isReport(rstRcvd)) {
+				qso = append(qso, rstSent)
+				if rstRcvd != "" {
+					qso = append(qso, rstRcvd)
+				}
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s has reports that can't be expressed in FLE [%s %s]", qsoLocation, rstSent, rstRcvd))
+			}
+		}
+		if stx := record.Get("STX") + record.Get("STX_STRING"); stx != "" {
+			qso = append(qso, ","+strings.Join(strings.Fields(stx), ""))
+		}
+		if srx := record.Get("SRX") + record.Get("SRX_STRING"); srx != "" {
+			qso = append(qso, "."+strings.Join(strings.Fields(srx), ""))
+		}
+		for _, reference := range []string{
+			adifSigReference(record, "WWFF_REF", "SIG", "SIG_INFO", "WWFF"),
+			record.Get("SOTA_REF"),
+			adifSigReference(record, "POTA_REF", "SIG", "SIG_INFO", "POTA"),
+		} {
+			if reference != "" {
+				qso = append(qso, reference)
+			}
+		}
+		//A FLE name is a single word
+		if name := strings.Fields(record.Get("NAME")); len(name) > 0 {
+			qso = append(qso, "@"+name[0])
+			if len(name) > 1 {
+				warnings = append(warnings, fmt.Sprintf("%s: only the first word of the name [%s] is kept", qsoLocation, record.Get("NAME")))
+			}
+		}
+		if grid := record.Get("GRIDSQUARE"); grid != "" {
+			qso = append(qso, "#"+grid)
+		}
+		if comment := record.Get("COMMENT"); comment != "" {
+			qso = append(qso, "<"+strings.NewReplacer("<", "(", ">", ")", "\n", " ", "\r", "").Replace(comment)+">")
+		}
+		if qslMessage := record.Get("QSLMSG"); qslMessage != "" {
+			qso = append(qso, "["+strings.NewReplacer("[", "(", "]", ")", "\n", " ", "\r", "").Replace(qslMessage)+"]")
+		}
+		output.WriteString(strings.Join(qso, " ") + "\n")
+	}
+	return output.String(), warnings
+}
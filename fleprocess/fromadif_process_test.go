@@ -0,0 +1,131 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_buildFleFromAdif(t *testing.T) {
+	records := []AdifRecord{
+		testAdifRecord(map[string]string{"STATION_CALLSIGN": "ON4KJM/P", "OPERATOR": "ON4KJM", "MY_SIG": "WWFF", "MY_SIG_INFO": "ONFF-0258", "CALL": "S57LC", "QSO_DATE": "20200524",
+			"TIME_ON": "131045", "BAND": "20M", "MODE": "CW", "RST_SENT": "599", "RST_RCVD": "599"}),
+		testAdifRecord(map[string]string{"STATION_CALLSIGN": "ON4KJM/P", "OPERATOR": "ON4KJM", "MY_SIG": "WWFF", "MY_SIG_INFO": "ONFF-0258", "CALL": "ON4LY", "QSO_DATE": "20200524",
+			"TIME_ON": "1312", "BAND": "20M", "MODE": "CW", "RST_SENT": "559", "NAME": "Jean Marc", "GRIDSQUARE": "JO20", "COMMENT": "a <big> signal"}),
+		testAdifRecord(map[string]string{"STATION_CALLSIGN": "ON4KJM/P", "MY_SIG": "WWFF", "MY_SIG_INFO": "ONFF-0258", "MY_SOTA_REF": "ON/ON-001", "CALL": "DL1AA", "QSO_DATE": "20200525",
+			"BAND": "40m", "FREQ": "7.032", "MODE": "MFSK", "SUBMODE": "FT4", "SOTA_REF": "DL/AL-044", "QSLMSG": "tnx"}),
+		testAdifRecord(map[string]string{"STATION_CALLSIGN": "ON4KJM/P", "MY_SIG": "WWFF", "MY_SIG_INFO": "ONFF-0258", "MY_SOTA_REF": "ON/ON-001", "CALL": "DL2BB", "QSO_DATE": "20200525",
+			"TIME_ON": "0800", "BAND": "40m", "FREQ": "7.032", "MODE": "DYNAMIC", "SUBMODE": "VARA HF"}),
+		testAdifRecord(map[string]string{"STATION_CALLSIGN": "ON4KJM/P", "MY_SIG": "WWFF", "MY_SIG_INFO": "ONFF-0258", "MY_SOTA_REF": "ON/ON-001", "CALL": "DL3CC", "QSO_DATE": "20200525",
+			"TIME_ON": "0805", "BAND": "40m", "FREQ": "7.032", "MODE": "FOO"}),
+	}
+	wantFle := "# Converted from test.adi\n" +
+		"\n" +
+		"mycall ON4KJM/P\n" +
+		"operator ON4KJM\n" +
+		"mywwff ONFF-0258\n" +
+		"\n" +
+		"date 2020-05-24 20m cw\n" +
+		"1310 S57LC\n" +
+		"1312 ON4LY 559 @Jean #JO20 <a (big) signal>\n" +
+		"mysota ON/ON-001\n" +
+		"date 2020-05-25 40m 7.032 ft4\n" +
+		"DL1AA DL/AL-044 [tnx]\n" +
+		"dynamic\n" +
+		"0800 DL2BB\n" +
+		"0805 DL3CC\n"
+	wantWarnings := []string{
+		"QSO #2 (ON4LY): only the first word of the name [Jean Marc] is kept",
+		"QSO #3 (DL1AA) has no operator: the previous value is used",
+		"QSO #4 (DL2BB) has an unsupported submode [VARA HF]: the mode is used",
+		"QSO #5 (DL3CC) has an unsupported mode [FOO]",
+	}
+
+	gotFle, gotWarnings := buildFleFromAdif(records, "test.adi")
+	if gotFle != wantFle {
+		t.Errorf("buildFleFromAdif() = \n%v, want \n%v", gotFle, wantFle)
+	}
+	if !reflect.DeepEqual(gotWarnings, wantWarnings) {
+		t.Errorf("buildFleFromAdif() gotWarnings = %v, want %v", gotWarnings, wantWarnings)
+	}
+}
+
+//An ADIF file generated by FLEcli converts back to a FLE log holding the same QSOs
+func Test_buildFleFromAdif_roundTrip(t *testing.T) {
+	input, _ := ioutil.ReadFile("../test/data/sample_wwff_sota.txt")
+	originalLog, _ := LoadLog(strings.NewReader(string(input)), false)
+
+	adifParams := AdifParams{IsWWFFcli: true, IsSOTAcli: true}
+	adifFile, err := ReadAdif(strings.NewReader(strings.Join(buildAdif(originalLog, adifParams), "\n")))
+	if err != nil {
+		t.Fatalf("ReadAdif() unexpected error: %v", err)
+	}
+	fleLog, warnings := buildFleFromAdif(adifFile.Records, "sample.adi")
+	if len(warnings) != 0 {
+		t.Errorf("buildFleFromAdif() unexpected warnings: %v", warnings)
+	}
+	convertedLog, diagnostics := LoadLog(strings.NewReader(fleLog), false)
+	if len(diagnostics) != 0 {
+		t.Fatalf("LoadLog() unexpected diagnostics: %v", diagnostics)
+	}
+
+	if got, want := buildAdif(convertedLog, adifParams), buildAdif(originalLog, adifParams); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip ADIF = %v, want %v", got, want)
+	}
+}
+
+func TestProcessFromAdifCommand(t *testing.T) {
+	directory, err := ioutil.TempDir("", "FLEcli-fromadif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	inputFilename := filepath.Join(directory, "activation.adi")
+	ioutil.WriteFile(inputFilename, []byte("<EOH>\n<STATION_CALLSIGN:8>ON4KJM/P <CALL:5>S57LC <QSO_DATE:8>20200524 <TIME_ON:4>1310 <BAND:3>20m <MODE:2>CW <EOR>\n"), 0644)
+	emptyFilename := filepath.Join(directory, "empty.adi")
+	ioutil.WriteFile(emptyFilename, []byte("<EOH>\n"), 0644)
+
+	if err := ProcessFromAdifCommand(FromAdifParams{InputFilename: inputFilename}); err != nil {
+		t.Fatalf("ProcessFromAdifCommand() unexpected error: %v", err)
+	}
+	wantFle := "# Converted from activation.adi\n\nmycall ON4KJM/P\n\ndate 2020-05-24 20m cw\n1310 S57LC\n"
+	if gotFle, _ := ioutil.ReadFile(filepath.Join(directory, "activation.txt")); string(gotFle) != wantFle {
+		t.Errorf("ProcessFromAdifCommand() wrote %v, want %v", string(gotFle), wantFle)
+	}
+
+	//The output file exists
+	if err := ProcessFromAdifCommand(FromAdifParams{InputFilename: inputFilename}); err == nil {
+		t.Errorf("ProcessFromAdifCommand() expected an error for an existing output file")
+	}
+	if err := ProcessFromAdifCommand(FromAdifParams{InputFilename: emptyFilename}); err == nil {
+		t.Errorf("ProcessFromAdifCommand() expected an error for a file without QSO")
+	}
+}
+
+//testAdifRecord builds a record from a map of field values
+func testAdifRecord(values map[string]string) (record AdifRecord) {
+	for name, value := range values {
+		record = append(record, AdifField{Name: name, Value: value})
+	}
+	return record
+}
@@ -3,8 +3,6 @@ package fleprocess
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"strings"
 	"time"
 )
@@ -104,8 +102,7 @@ func (tb *InferTimeBlock) storeTimeGap(logline LogLine, position int) (bool, err
 				return false, errors.New("Date not defined or badly formated")
 			}
 			if tb.lastRecordedTime, err = time.Parse(ADIFdateTimeFormat, logline.Date+" "+logline.ActualTime); err != nil {
-				log.Println("Fatal error during internal date conversion: ", err)
-				os.Exit(1)
+				return false, fmt.Errorf("Error during internal date conversion: %s", err)
 			}
 			tb.logFilePosition = position
 		} else {
@@ -114,8 +111,7 @@ func (tb *InferTimeBlock) storeTimeGap(logline LogLine, position int) (bool, err
 				return false, errors.New("Gap start time is empty")
 			}
 			if tb.nextValidTime, err = time.Parse(ADIFdateTimeFormat, logline.Date+" "+logline.ActualTime); err != nil {
-				log.Println("Fatal error during internal date conversion: ", err)
-				os.Exit(1)
+				return false, fmt.Errorf("Error during internal date conversion: %s", err)
 			}
 			return true, nil
 		}
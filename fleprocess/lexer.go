@@ -0,0 +1,239 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//TokenType is the category of a FLE token
+type TokenType int
+
+//Token types. The lexer classifies the elements by their shape. The types after TokenUnknown
+//are set by the parser as they depend on the position of the element in the line.
+const (
+	TokenWord TokenType = iota //element with no specific shape
+	TokenMode
+	TokenBand
+	TokenFrequency
+	TokenDate
+	TokenDateKeyword
+	TokenDayKeyword
+	TokenDayIncrement
+	TokenNumber       //time or report, depending on the position
+	TokenSignedNumber //dB report of the digital modes
+	TokenCall
+	TokenName
+	TokenGrid
+	TokenSentExchange
+	TokenRcvdExchange
+	TokenWwffKeyword
+	TokenSotaKeyword
+	TokenPotaKeyword
+	TokenWwffRef
+	TokenSotaRef
+	TokenPotaRef
+	TokenComment
+	TokenQslMessage
+	TokenUnknown //element that makes no sense at its position
+	TokenTime
+	TokenSentReport
+	TokenRcvdReport
+)
+
+var tokenTypeNames = []string{"word", "mode", "band", "frequency", "date", "date keyword", "day keyword", "day increment",
+	"number", "signed number", "call", "name", "grid", "sent exchange", "received exchange", "wwff keyword", "sota keyword", "pota keyword",
+	"wwff reference", "sota reference", "pota reference", "comment", "QSL message", "unknown", "time", "sent report", "received report"}
+
+//String returns the name of the token type
+func (tokenType TokenType) String() string {
+	if int(tokenType) < len(tokenTypeNames) {
+		return tokenTypeNames[tokenType]
+	}
+	return fmt.Sprintf("TokenType(%d)", int(tokenType))
+}
+
+//Token is an element of a FLE line with its position in the input
+type Token struct {
+	Type TokenType
+	//Text is the element as found in the input (without the brackets for comments and QSL messages)
+	Text string
+	//Line is the line number in the input (starting at 1), 0 if unknown
+	Line int
+	//Column is the position of the first character of the element (starting at 1)
+	Column int
+	//Length is the length of the element in the input (brackets included)
+	Length int
+}
+
+//Source returns the element as written in the input
+func (token Token) Source() string {
+	switch token.Type {
+	case TokenComment:
+		return "<" + token.Text + ">"
+	case TokenQslMessage:
+		return "[" + token.Text + "]"
+	}
+	return token.Text
+}
+
+//bracketTokens maps the opening brackets to their closing bracket and token type
+var bracketTokens = map[byte]struct {
+	closing   byte
+	tokenType TokenType
+}{
+	'<': {'>', TokenComment},
+	'[': {']', TokenQslMessage},
+}
+
+//lexLine cuts a data line in tokens. The blank separated elements are classified by their shape.
+//Comments (<...>) and QSL messages ([...]) can contain blanks and are not necessarily separated from the other elements.
+func lexLine(line string, lineNumber int) (tokens []Token) {
+	start := -1
+	closeWord := func(end int) {
+		if start >= 0 {
+			text := line[start:end]
+			tokens = append(tokens, Token{Type: classifyElement(text), Text: text, Line: lineNumber, Column: start + 1, Length: end - start})
+			start = -1
+		}
+	}
+
+	for i := 0; i < len(line); {
+		c, size := utf8.DecodeRuneInString(line[i:])
+		if unicode.IsSpace(c) {
+			closeWord(i)
+			i += size
+			continue
+		}
+		if bracket, isBracket := bracketTokens[line[i]]; isBracket {
+			if length := strings.IndexByte(line[i+1:], bracket.closing); length >= 0 {
+				closeWord(i)
+				tokens = append(tokens, Token{Type: bracket.tokenType, Text: line[i+1 : i+1+length], Line: lineNumber, Column: i + 1, Length: length + 2})
+				i += length + 2
+				continue
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+		i += size
+	}
+	closeWord(len(line))
+	return tokens
+}
+
+var regexpIsFullTime = regexp.MustCompile("^[0-2]{1}[0-9]{3}$")
+var regexpIsTimePart = regexp.MustCompile("^[0-5]{1}[0-9]{1}$|^[1-9]{1}$")
+var regexpIsOMname = regexp.MustCompile("^@")
+var regexpIsGridLoc = regexp.MustCompile("^#")
+var regexpIsRst = regexp.MustCompile("^[\\d]{1,3}$")
+var regexpIsNumber = regexp.MustCompile("^[\\d]+$")
+var regexpIsDbReport = regexp.MustCompile("^[+-][\\d]{1,2}$")
+var regexpIsFreq = regexp.MustCompile("^[\\d]+\\.[\\d]+$")
+var regexpIsFreqWithUnit = regexp.MustCompile("^([\\d]+(\\.[\\d]+)?)([kKMGg])$")
+var regexpIsKhzFreq = regexp.MustCompile("^[\\d]{4,}$")
+var regexpIsSotaKeyWord = regexp.MustCompile("(?i)^sota$")
+var regexpIsWwffKeyWord = regexp.MustCompile("(?i)^wwff$")
+var regexpIsPotaKeyWord = regexp.MustCompile("(?i)^pota$")
+var regexpDatePattern = regexp.MustCompile("^(\\d{2}|\\d{4})[-/ .]\\d{1,2}[-/ .]\\d{1,2}$")
+var regexpIsDateKeyWord = regexp.MustCompile("(?i)^date$")
+var regexpDayIncrementPattern = regexp.MustCompile("^\\++$")
+var regexpIsDayKeyword = regexp.MustCompile("(?i)^day$")
+var regexpIsSentExchange = regexp.MustCompile("^,[0-9a-zA-Z/]+$")
+var regexpIsRcvdExchange = regexp.MustCompile("^\\.[0-9a-zA-Z/]+$")
+
+//isReport returns true if the element has the shape of a report (RST or signed dB value)
+func isReport(element string) bool {
+	return regexpIsRst.MatchString(element) || regexpIsDbReport.MatchString(element)
+}
+
+//frequencyInMhz converts a frequency element to MHz. A decimal value is in MHz unless it has a unit suffix
+//(k, M or G). An integer without unit is in kHz (isKhzInteger is true).
+func frequencyInMhz(element string) (frequency float64, isKhzInteger bool) {
+	if regexpIsKhzFreq.MatchString(element) {
+		frequency, _ = strconv.ParseFloat(element, 64)
+		return frequency / 1000, true
+	}
+	if match := regexpIsFreqWithUnit.FindStringSubmatch(element); match != nil {
+		frequency, _ = strconv.ParseFloat(match[1], 64)
+		switch match[3] {
+		case "k", "K":
+			return frequency / 1000, false
+		case "G", "g":
+			return frequency * 1000, false
+		}
+		return frequency, false
+	}
+	frequency, _ = strconv.ParseFloat(element, 64)
+	return frequency, false
+}
+
+//classifyElement returns the type of a blank separated element, based on its shape only.
+//The order of the checks matters: "cw" is a mode, not a word, and "on4kjm" is a call.
+func classifyElement(element string) TokenType {
+	switch {
+	case lookupMode(strings.ToUpper(element)):
+		return TokenMode
+	case regexpDatePattern.MatchString(element):
+		return TokenDate
+	case regexpIsDateKeyWord.MatchString(element):
+		return TokenDateKeyword
+	case regexpIsDayKeyword.MatchString(element):
+		return TokenDayKeyword
+	case regexpDayIncrementPattern.MatchString(element):
+		return TokenDayIncrement
+	}
+	if isBandElement, _, _, _ := IsBand(element); isBandElement {
+		return TokenBand
+	}
+	switch {
+	case regexpIsFreq.MatchString(element), regexpIsFreqWithUnit.MatchString(element):
+		return TokenFrequency
+	case regexpIsSentExchange.MatchString(element):
+		return TokenSentExchange
+	case regexpIsRcvdExchange.MatchString(element):
+		return TokenRcvdExchange
+	case !regexpIsGridLoc.MatchString(element) && validCallRegexp.MatchString(strings.ToUpper(element)):
+		return TokenCall
+	case regexpIsNumber.MatchString(element):
+		return TokenNumber
+	case regexpIsDbReport.MatchString(element):
+		return TokenSignedNumber
+	case regexpIsOMname.MatchString(element):
+		return TokenName
+	case regexpIsGridLoc.MatchString(element):
+		return TokenGrid
+	case regexpIsWwffKeyWord.MatchString(element):
+		return TokenWwffKeyword
+	case regexpIsSotaKeyWord.MatchString(element):
+		return TokenSotaKeyword
+	case regexpIsPotaKeyWord.MatchString(element):
+		return TokenPotaKeyword
+	case validWwffRegexp.MatchString(strings.ToUpper(element)):
+		return TokenWwffRef
+	case validSotaRegexp.MatchString(strings.ToUpper(element)):
+		return TokenSotaRef
+	case validPotaRegexp.MatchString(strings.ToUpper(element)):
+		return TokenPotaRef
+	}
+	return TokenWord
+}
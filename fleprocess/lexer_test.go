@@ -0,0 +1,184 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_lexLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		wantTokens []Token
+	}{
+		{
+			"blank separated elements",
+			"40m  cw\t on4kjm  ",
+			[]Token{
+				{Type: TokenBand, Text: "40m", Line: 3, Column: 1, Length: 3},
+				{Type: TokenMode, Text: "cw", Line: 3, Column: 6, Length: 2},
+				{Type: TokenCall, Text: "on4kjm", Line: 3, Column: 10, Length: 6},
+			},
+		},
+		{
+			"comment with blanks",
+			"aaaa <bracketed text> bbbbb",
+			[]Token{
+				{Type: TokenWord, Text: "aaaa", Line: 3, Column: 1, Length: 4},
+				{Type: TokenComment, Text: "bracketed text", Line: 3, Column: 6, Length: 16},
+				{Type: TokenWord, Text: "bbbbb", Line: 3, Column: 23, Length: 5},
+			},
+		},
+		{
+			"QSL message at right",
+			"aaaa bbbbb [bracketed text]",
+			[]Token{
+				{Type: TokenWord, Text: "aaaa", Line: 3, Column: 1, Length: 4},
+				{Type: TokenWord, Text: "bbbbb", Line: 3, Column: 6, Length: 5},
+				{Type: TokenQslMessage, Text: "bracketed text", Line: 3, Column: 12, Length: 16},
+			},
+		},
+		{
+			"empty brackets",
+			"aaaa <> bbbbb",
+			[]Token{
+				{Type: TokenWord, Text: "aaaa", Line: 3, Column: 1, Length: 4},
+				{Type: TokenComment, Text: "", Line: 3, Column: 6, Length: 2},
+				{Type: TokenWord, Text: "bbbbb", Line: 3, Column: 9, Length: 5},
+			},
+		},
+		{
+			"concatenated",
+			"aaaa<bracketed text>bbbbb",
+			[]Token{
+				{Type: TokenWord, Text: "aaaa", Line: 3, Column: 1, Length: 4},
+				{Type: TokenComment, Text: "bracketed text", Line: 3, Column: 5, Length: 16},
+				{Type: TokenWord, Text: "bbbbb", Line: 3, Column: 21, Length: 5},
+			},
+		},
+		{
+			"unclosed bracket",
+			"aaaa <bbbbb",
+			[]Token{
+				{Type: TokenWord, Text: "aaaa", Line: 3, Column: 1, Length: 4},
+				{Type: TokenWord, Text: "<bbbbb", Line: 3, Column: 6, Length: 6},
+			},
+		},
+		{
+			"empty line",
+			"   ",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotTokens := lexLine(tt.line, 3); !reflect.DeepEqual(gotTokens, tt.wantTokens) {
+				t.Errorf("lexLine() = %v, want %v", gotTokens, tt.wantTokens)
+			}
+		})
+	}
+}
+
+func Test_classifyElement(t *testing.T) {
+	tests := []struct {
+		element string
+		want    TokenType
+	}{
+		{"cw", TokenMode},
+		{"SSB", TokenMode},
+		{"2020-05-23", TokenDate},
+		{"date", TokenDateKeyword},
+		{"DAY", TokenDayKeyword},
+		{"++", TokenDayIncrement},
+		{"20m", TokenBand},
+		{"14.045", TokenFrequency},
+		{"14062k", TokenFrequency},
+		{"144.300M", TokenFrequency},
+		{"10.368G", TokenFrequency},
+		{"2M", TokenBand},
+		{",023", TokenSentExchange},
+		{".on/dx", TokenRcvdExchange},
+		{"on4kjm/p", TokenCall},
+		{"jt9a", TokenCall},
+		{"ft4", TokenMode},
+		{"usb", TokenMode},
+		{"1234", TokenNumber},
+		{"-15", TokenSignedNumber},
+		{"+3", TokenSignedNumber},
+		{"+", TokenDayIncrement},
+		{"-153", TokenWord},
+		{"@Jean", TokenName},
+		{"#jo40", TokenGrid},
+		{"wwff", TokenWwffKeyword},
+		{"Sota", TokenSotaKeyword},
+		{"pota", TokenPotaKeyword},
+		{"onff-0258", TokenWwffRef},
+		{"on/on-001", TokenSotaRef},
+		{"on-0001", TokenPotaRef},
+		{"foo", TokenWord},
+	}
+	for _, tt := range tests {
+		t.Run(tt.element, func(t *testing.T) {
+			if got := classifyElement(tt.element); got != tt.want {
+				t.Errorf("classifyElement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_frequencyInMhz(t *testing.T) {
+	tests := []struct {
+		element          string
+		wantFrequency    float64
+		wantIsKhzInteger bool
+	}{
+		{"7.018", 7.018, false},
+		{"7018", 7.018, true},
+		{"14062k", 14.062, false},
+		{"14062.5K", 14.0625, false},
+		{"144.300M", 144.3, false},
+		{"10.368G", 10368, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.element, func(t *testing.T) {
+			gotFrequency, gotIsKhzInteger := frequencyInMhz(tt.element)
+			if gotFrequency != tt.wantFrequency || gotIsKhzInteger != tt.wantIsKhzInteger {
+				t.Errorf("frequencyInMhz() = %v, %v, want %v, %v", gotFrequency, gotIsKhzInteger, tt.wantFrequency, tt.wantIsKhzInteger)
+			}
+		})
+	}
+}
+
+func TestToken_Source(t *testing.T) {
+	tests := []struct {
+		token Token
+		want  string
+	}{
+		{Token{Type: TokenComment, Text: "a comment"}, "<a comment>"},
+		{Token{Type: TokenQslMessage, Text: "tnx"}, "[tnx]"},
+		{Token{Type: TokenCall, Text: "on4kjm"}, "on4kjm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.token.Source(); got != tt.want {
+				t.Errorf("Token.Source() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
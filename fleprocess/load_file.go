@@ -17,357 +17,103 @@ limitations under the License.
 */
 
 import (
-	"bufio"
 	"fmt"
-	"log"
+	"io"
 	"os"
-	"regexp"
-	"strings"
-	"time"
 )
 
-//LoadFile FIXME:
-//returns nill if failure to process
+//LoadFile loads and validates a FLE log file for the command line commands.
+//The loaded log and the problems found are displayed. isProcessedOK is false if errors were found.
 func LoadFile(inputFilename string, isInterpolateTime bool) (filleFullLog []LogLine, isProcessedOK bool) {
-	file, err := os.Open(inputFilename)
+	fullLog, diagnostics := LoadLogFile(inputFilename, isInterpolateTime)
 
-	if err != nil {
-		log.Fatalf("failed opening file: %s", err)
-	}
+	displayLogSimple(fullLog)
 
-	scanner := bufio.NewScanner(file)
-	scanner.Split(bufio.ScanLines)
-	var txtlines []string
+	isProcessedOK = printLoadResult(len(fullLog), diagnostics)
+	return fullLog, isProcessedOK
+}
 
-	for scanner.Scan() {
-		txtlines = append(txtlines, scanner.Text())
+//printLoadResult displays the problems found while loading the log, if any.
+//It returns false if errors were found.
+func printLoadResult(qsoCount int, diagnostics []Diagnostic) bool {
+	//Display parsing errors, if any
+	if len(diagnostics) != 0 {
+		fmt.Println("\nProcessing errors:")
+		for _, diagnostic := range diagnostics {
+			fmt.Println(diagnostic)
+		}
 	}
-
-	if error := scanner.Err(); error != nil {
-		log.Fatal(error)
+	if HasErrors(diagnostics) {
+		return false
 	}
 
-	file.Close()
-
-	//isInferTimeFatalError is set to true is something bad happened while storing time gaps.
-	isInferTimeFatalError := false
-
-	regexpLineComment := regexp.MustCompile("^[[:blank:]]*#")
-	regexpOnlySpaces := regexp.MustCompile("^\\s+$")
-	regexpSingleMultiLineComment := regexp.MustCompile("^[[:blank:]]*{.+}$")
-	regexpStartMultiLineComment := regexp.MustCompile("^[[:blank:]]*{")
-	regexpEndMultiLineComment := regexp.MustCompile("}$")
-	regexpHeaderMyCall := regexp.MustCompile("(?i)^mycall ")
-	regexpHeaderOperator := regexp.MustCompile("(?i)^operator ")
-	regexpHeaderMyWwff := regexp.MustCompile("(?i)^mywwff ")
-	regexpHeaderMySota := regexp.MustCompile("(?i)^mysota ")
-	regexpHeaderMyGrid := regexp.MustCompile("(?i)^mygrid ")
-	regexpHeaderQslMsg := regexp.MustCompile("(?i)^qslmsg ")
-	regexpHeaderNickname := regexp.MustCompile("(?i)^nickname ")
-
-	headerMyCall := ""
-	headerOperator := ""
-	headerMyWWFF := ""
-	headerMySOTA := ""
-	headerMyGrid := ""
-	headerQslMsg := ""
-	headerNickname := ""
-	//headerDate := ""
-	lineCount := 0
-
-	wrkTimeBlock := InferTimeBlock{}
-	missingTimeBlockList := []InferTimeBlock{}
-
-	var isInMultiLine = false
-	var cleanedInput []string
-	var errorLog []string
-
-	var previousLogLine LogLine
-	fullLog := []LogLine{}
-
-	//Loop through all the stored lined
-	for _, eachline := range txtlines {
-		lineCount++
-
-		// ****
-		// ** Lets do some house keeping first by droping the unecessary lines
-		// ****
-
-		//Skip the line if it starts with "#"
-		if regexpLineComment.MatchString(eachline) {
-			continue
-		}
-		//Skip if line is empty or blank
-		if (len(eachline) == 0) || (regexpOnlySpaces.MatchString(eachline)) {
-			continue
-		}
-
-		// Process multi-line comments
-		if regexpStartMultiLineComment.MatchString(eachline) {
-			//Single-line "multi-line" coment
-			if regexpSingleMultiLineComment.MatchString(eachline) {
-				continue
-			}
-			isInMultiLine = true
-			continue
-		}
-		if isInMultiLine {
-			if regexpEndMultiLineComment.MatchString(eachline) {
-				isInMultiLine = false
-			}
-			continue
-		}
-
-		// ****
-		// ** Process the Header block
-		// ****
-
-		//My Call
-		if regexpHeaderMyCall.MatchString(eachline) {
-			//Attempt to redefine value
-			if headerMyCall != "" {
-				errorLog = append(errorLog, fmt.Sprintf("Attempt to redefine MyCall at line %d", lineCount))
-				continue
-			}
-			errorMsg := ""
-			myCallList := regexpHeaderMyCall.Split(eachline, -1)
-			if len(strings.TrimSpace(myCallList[1])) > 0 {
-				headerMyCall, errorMsg = ValidateCall(strings.TrimSpace(myCallList[1]))
-				cleanedInput = append(cleanedInput, fmt.Sprintf("My call: %s", headerMyCall))
-				if len(errorMsg) != 0 {
-					errorLog = append(errorLog, fmt.Sprintf("Invalid myCall at line %d: %s (%s)", lineCount, myCallList[1], errorMsg))
-				}
-			}
-			//If there is no data after the marker, we just skip the data.
-			continue
-		}
-
-		//Operator
-		if regexpHeaderOperator.MatchString(eachline) {
-			//Attempt to redefine value
-			if headerOperator != "" {
-				errorLog = append(errorLog, fmt.Sprintf("Attempt to redefine Operator at line %d", lineCount))
-				continue
-			}
-			errorMsg := ""
-			myOperatorList := regexpHeaderOperator.Split(eachline, -1)
-			if len(strings.TrimSpace(myOperatorList[1])) > 0 {
-				headerOperator, errorMsg = ValidateCall(strings.TrimSpace(myOperatorList[1]))
-				cleanedInput = append(cleanedInput, fmt.Sprintf("Operator: %s", headerOperator))
-				if len(errorMsg) != 0 {
-					errorLog = append(errorLog, fmt.Sprintf("Invalid Operator at line %d: %s (%s)", lineCount, myOperatorList[1], errorMsg))
-				}
-			}
-			//If there is no data after the marker, we just skip the data.
-			continue
-		}
-
-		// My WWFF
-		if regexpHeaderMyWwff.MatchString(eachline) {
-			//Attempt to redefine value
-			if headerMyWWFF != "" {
-				errorLog = append(errorLog, fmt.Sprintf("Attempt to redefine MyWWFF at line %d", lineCount))
-				continue
-			}
-			errorMsg := ""
-			myWwffList := regexpHeaderMyWwff.Split(eachline, -1)
-			if len(strings.TrimSpace(myWwffList[1])) > 0 {
-				headerMyWWFF, errorMsg = ValidateWwff(strings.TrimSpace(myWwffList[1]))
-				cleanedInput = append(cleanedInput, fmt.Sprintf("My WWFF: %s", headerMyWWFF))
-				if len(errorMsg) != 0 {
-					errorLog = append(errorLog, fmt.Sprintf("Invalid \"My WWFF\" at line %d: %s (%s)", lineCount, myWwffList[1], errorMsg))
-				}
-			}
-			//If there is no data after the marker, we just skip the data.
-			continue
-		}
-
-		//My Sota
-		if regexpHeaderMySota.MatchString(eachline) {
-			//Attempt to redefine value
-			if headerMySOTA != "" {
-				errorLog = append(errorLog, fmt.Sprintf("Attempt to redefine MySOTA at line %d", lineCount))
-				continue
-			}
-			errorMsg := ""
-			mySotaList := regexpHeaderMySota.Split(eachline, -1)
-			if len(strings.TrimSpace(mySotaList[1])) > 0 {
-				headerMySOTA, errorMsg = ValidateSota(strings.TrimSpace(mySotaList[1]))
-				cleanedInput = append(cleanedInput, fmt.Sprintf("My Sota: %s", headerMySOTA))
-				if len(errorMsg) != 0 {
-					errorLog = append(errorLog, fmt.Sprintf("Invalid \"My SOTA\" at line %d: %s (%s)", lineCount, mySotaList[1], errorMsg))
-				}
-			}
-			//If there is no data after the marker, we just skip the data.
-			continue
-		}
-
-		//My Grid
-		if regexpHeaderMyGrid.MatchString(eachline) {
-			//Attempt to redefine value
-			if headerMyGrid != "" {
-				errorLog = append(errorLog, fmt.Sprintf("Attempt to redefine MyGrid at line %d", lineCount))
-				continue
-			}
-			errorMsg := ""
-			myGridList := regexpHeaderMyGrid.Split(eachline, -1)
-			if len(strings.TrimSpace(myGridList[1])) > 0 {
-				headerMyGrid, errorMsg = ValidateGridLocator(strings.TrimSpace(myGridList[1]))
-				cleanedInput = append(cleanedInput, fmt.Sprintf("My Grid: %s", headerMyGrid))
-				if len(errorMsg) != 0 {
-					errorLog = append(errorLog, fmt.Sprintf("Invalid \"My Grid\" at line %d: %s (%s)", lineCount, myGridList[1], errorMsg))
-				}
-			}
-			//If there is no data after the marker, we just skip the data.
-			continue
-		}
-
-		//QSL Message
-		if regexpHeaderQslMsg.MatchString(eachline) {
-			myQslMsgList := regexpHeaderQslMsg.Split(eachline, -1)
-			if len(myQslMsgList[1]) > 0 {
-				headerQslMsg = myQslMsgList[1]
-				cleanedInput = append(cleanedInput, fmt.Sprintf("QSL Message: %s", headerQslMsg))
-			}
-			//If there is no data after the marker, we just skip the data.
-			continue
-		}
-
-		//Nickname
-		if regexpHeaderNickname.MatchString(eachline) {
-			//Attempt to redefine value
-			if headerNickname != "" {
-				errorLog = append(errorLog, fmt.Sprintf("Attempt to redefine eQSL Nickname at line %d", lineCount))
-				continue
-			}
-			myNicknameList := regexpHeaderNickname.Split(eachline, -1)
-			if len(strings.TrimSpace(myNicknameList[1])) > 0 {
-				headerNickname = strings.TrimSpace(myNicknameList[1])
-				cleanedInput = append(cleanedInput, fmt.Sprintf("eQSL Nickmane: %s", headerNickname))
-			}
-			//If there is no data after the marker, we just skip the data.
-			continue
-		}
-
-		// ****
-		// ** Process the data block
-		// ****
-
-		// Load the header values in the previousLogLine
-		previousLogLine.MyCall = headerMyCall
-		previousLogLine.Operator = headerOperator
-		previousLogLine.MyWWFF = headerMyWWFF
-		previousLogLine.MySOTA = headerMySOTA
-		previousLogLine.MyGrid = headerMyGrid
-		previousLogLine.QSLmsg = headerQslMsg //previousLogLine.QslMsg is redundant
-		previousLogLine.Nickname = headerNickname
-
-		//parse a line
-		logline, errorLine := ParseLine(eachline, previousLogLine)
-
-		//we have a valid line (contains a call)
-		if logline.Call != "" {
-			fullLog = append(fullLog, logline)
-
-			//store time inference data
-			if isInterpolateTime && !isInferTimeFatalError {
-				var isEndOfGap bool
-				if isEndOfGap, err = wrkTimeBlock.storeTimeGap(logline, len(fullLog)); err != nil {
-					errorLog = append(errorLog, fmt.Sprintf("Fatal error at line %d: %s", lineCount, err))
-					isInferTimeFatalError = true
-				}
-				//If we reached the end of the time gap, we make the necessary checks and make our gap calculation
-				if isEndOfGap {
-					if err := wrkTimeBlock.finalizeTimeGap(); err != nil {
-						//If an error occured it is a fatal error
-						errorLog = append(errorLog, fmt.Sprintf("Fatal error at line %d: %s", lineCount, err))
-						isInferTimeFatalError = true
-					}
-
-					if isInferTimeFatalError {
-						break
-					}
-
-					//add it to the gap collection
-					missingTimeBlockList = append(missingTimeBlockList, wrkTimeBlock)
-
-					//create a new block
-					wrkTimeBlock = InferTimeBlock{}
-
-					//Store this record in the new block as a new gap might be following
-					//no error or endOfGap processing as it has already been successfully processed
-					wrkTimeBlock.storeTimeGap(logline, len(fullLog))
-				}
-			}
-		}
-
-		//Store append the accumulated soft parsing errors into the global parsing error log file
-		if errorLine != "" {
-			errorLog = append(errorLog, fmt.Sprintf("Parsing error at line %d: %s ", lineCount, errorLine))
-		}
-
-		//store the current logline so that it can be used as a model when parsing the next line
-		previousLogLine = logline
+	fmt.Println("\nSuccessfully parsed ", qsoCount, " QSOs.")
+	return true
+}
 
-		//We go back to the top to process the next loaded log line (Continue not necessary here)
+//LoadLogFile loads and validates a FLE log file.
+//It neither prints nor exits: the problems found (including a file that can't be read) are returned as diagnostics.
+func LoadLogFile(inputFilename string, isInterpolateTime bool) (fullLog []LogLine, diagnostics []Diagnostic) {
+	file, err := os.Open(inputFilename)
+	if err != nil {
+		return nil, fileAccessDiagnostics("failed opening file", err)
 	}
+	defer file.Close()
 
-	//***
-	//*** We have done processing the log file, so let's post process it
-	//***
+	return LoadLog(file, isInterpolateTime)
+}
 
-	//if asked to infer the date, lets update the loaded logfile accordingly
-	if isInterpolateTime {
-		//Do we have an open timeBlok that has not been closed.
-		if (wrkTimeBlock.noTimeCount > 0) && (wrkTimeBlock.nextValidTime.IsZero()) {
-			errorLog = append(errorLog, fmt.Sprint("Fatal error: missing new time to infer time"))
-		} else {
-			for _, timeBlock := range missingTimeBlockList {
-				if err := timeBlock.validateTimeGap(); err != nil {
-					errorLog = append(errorLog, fmt.Sprintf("Fatal error: %s", err))
-					break
-				}
-				for i := 0; i < timeBlock.noTimeCount; i++ {
-					position := timeBlock.logFilePosition + i
-					pLogLine := &fullLog[position]
+//LoadLog reads and validates a FLE log.
+//It returns the QSOs and the list of the problems found. It neither prints nor exits.
+func LoadLog(reader io.Reader, isInterpolateTime bool) (fullLog []LogLine, diagnostics []Diagnostic) {
+	logReader := NewLogReader(reader, isInterpolateTime)
 
-					durationOffset := timeBlock.deltatime * time.Duration(i+1)
-					newTime := timeBlock.lastRecordedTime.Add(durationOffset)
-					updatedTimeString := newTime.Format("1504")
-					pLogLine.Time = updatedTimeString
-				}
-			}
+	fullLog = []LogLine{}
+	for {
+		logLine, isRead := logReader.Next()
+		if !isRead {
+			break
 		}
+		fullLog = append(fullLog, logLine)
 	}
 
-	displayLogSimple(fullLog)
-
-	//Display parsing errors, if any
-	if len(errorLog) != 0 {
-		fmt.Println("\nProcessing errors:")
-		for _, errorLogLine := range errorLog {
-			fmt.Println(errorLogLine)
-		}
-		isProcessedOK = false
-	} else {
-		fmt.Println("\nSuccessfully parsed ", lineCount, " lines.")
-		isProcessedOK = true
+	if err := logReader.Err(); err != nil {
+		return nil, fileAccessDiagnostics("failed reading input", err)
 	}
+	return fullLog, logReader.Diagnostics()
+}
 
-	return fullLog, isProcessedOK
-
+//fileAccessDiagnostics reports an input that can't be read as a fatal problem
+func fileAccessDiagnostics(message string, err error) []Diagnostic {
+	return []Diagnostic{{Code: CodeFileAccess, Severity: SeverityFatal, Message: fmt.Sprintf("%s: %s", message, err)}}
 }
 
 //displayLogSimple will print to stdout a simplified dump of a full log
+//The header values are repeated for every segment of the log
 func displayLogSimple(fullLog []LogLine) {
-	firstLine := true
+	printer := logPrinter{}
 	for _, filledLogLine := range fullLog {
-		if firstLine {
-			fmt.Println(SprintHeaderValues(filledLogLine))
-			fmt.Print(SprintColumnTitles())
-			firstLine = false
-		}
-		fmt.Print(SprintLogInColumn(filledLogLine))
+		printer.print(filledLogLine)
 	}
+}
 
+//logPrinter prints a simplified dump of a log, one QSO at a time.
+//The header values are printed at the start of every segment of the log.
+type logPrinter struct {
+	qsoCount        int
+	previousLogLine LogLine
+}
+
+//print displays the next QSO of the log
+func (printer *logPrinter) print(logLine LogLine) {
+	if printer.qsoCount == 0 || !isSameHeader(printer.previousLogLine, logLine) {
+		if printer.qsoCount > 0 {
+			fmt.Println("")
+		}
+		fmt.Println(SprintHeaderValues(logLine))
+		fmt.Print(SprintColumnTitles())
+	}
+	fmt.Print(SprintLogInColumn(logLine))
+	printer.qsoCount++
+	printer.previousLogLine = logLine
 }
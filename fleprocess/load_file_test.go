@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -41,6 +43,7 @@ func TestLoadFile_happyCase(t *testing.T) {
 	dataArray = append(dataArray, "nickname Portable")
 	dataArray = append(dataArray, "myWwff onff-0258")
 	dataArray = append(dataArray, "mySota on/on-001")
+	dataArray = append(dataArray, "myPota on-00259")
 	dataArray = append(dataArray, "myGrid jo50")
 	dataArray = append(dataArray, "QslMsg This is a QSL message")
 	dataArray = append(dataArray, " ")
@@ -83,6 +86,10 @@ func TestLoadFile_happyCase(t *testing.T) {
 	if loadedLogFile[0].MySOTA != expectedValue {
 		t.Errorf("Not the expected MySOTA value: %s (expecting %s)", loadedLogFile[0].MySOTA, expectedValue)
 	}
+	expectedValue = "ON-00259"
+	if loadedLogFile[0].MyPOTA != expectedValue {
+		t.Errorf("Not the expected MyPOTA value: %s (expecting %s)", loadedLogFile[0].MyPOTA, expectedValue)
+	}
 	expectedValue = "This is a QSL message"
 	if loadedLogFile[0].QSLmsg != expectedValue {
 		t.Errorf("Not the expected QSL Message from Header value: %s (expecting %s)", loadedLogFile[0].QSLmsg, expectedValue)
@@ -381,7 +388,7 @@ func TestLoadFile_happyCase_day(t *testing.T) {
 	os.Remove(temporaryDataFileName)
 }
 
-func TestLoadFile_redefining_myCall_must_fail(t *testing.T) {
+func TestLoadFile_redefining_myCall_starts_new_segment(t *testing.T) {
 
 	//Given
 	dataArray := make([]string, 0)
@@ -394,6 +401,7 @@ func TestLoadFile_redefining_myCall_must_fail(t *testing.T) {
 	dataArray = append(dataArray, "20/5/23")
 	dataArray = append(dataArray, "40m cw 0950 ik5zve/5 9 5")
 	dataArray = append(dataArray, "myCall on4do")
+	dataArray = append(dataArray, "0955 on6zq")
 
 	temporaryDataFileName := createTestFile(dataArray)
 
@@ -401,11 +409,11 @@ func TestLoadFile_redefining_myCall_must_fail(t *testing.T) {
 	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
 
 	//Then
-	if isLoadedOK {
-		t.Error("Test file processing should return with an error")
+	if !isLoadedOK {
+		t.Error("Test file could not be correctly processed")
 	}
-	if len(loadedLogFile) == 0 {
-		t.Error("No data loaded")
+	if len(loadedLogFile) != 2 {
+		t.Fatalf("Expected 2 QSOs, got %d", len(loadedLogFile))
 	}
 
 	expectedValue := "ON4KJM/P"
@@ -413,11 +421,16 @@ func TestLoadFile_redefining_myCall_must_fail(t *testing.T) {
 		t.Errorf("Not the expected MyCall value: %s (expecting %s)", loadedLogFile[0].MyCall, expectedValue)
 	}
 
+	expectedValue = "ON4DO"
+	if loadedLogFile[1].MyCall != expectedValue {
+		t.Errorf("Not the expected MyCall value in the new segment: %s (expecting %s)", loadedLogFile[1].MyCall, expectedValue)
+	}
+
 	//Clean Up
 	os.Remove(temporaryDataFileName)
 }
 
-func TestLoadFile_redefining_myWWFF_must_fail(t *testing.T) {
+func TestLoadFile_redefining_myWWFF_starts_new_segment(t *testing.T) {
 
 	//Given
 	dataArray := make([]string, 0)
@@ -432,6 +445,7 @@ func TestLoadFile_redefining_myWWFF_must_fail(t *testing.T) {
 	dataArray = append(dataArray, "20/5/23")
 	dataArray = append(dataArray, "40m cw 0950 ik5zve/5 9 5")
 	dataArray = append(dataArray, "myWWFF onff-0001")
+	dataArray = append(dataArray, "0955 on6zq")
 
 	temporaryDataFileName := createTestFile(dataArray)
 
@@ -439,11 +453,11 @@ func TestLoadFile_redefining_myWWFF_must_fail(t *testing.T) {
 	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
 
 	//Then
-	if isLoadedOK {
-		t.Error("Test file processing should return with an error")
+	if !isLoadedOK {
+		t.Error("Test file could not be correctly processed")
 	}
-	if len(loadedLogFile) == 0 {
-		t.Error("No data loaded")
+	if len(loadedLogFile) != 2 {
+		t.Fatalf("Expected 2 QSOs, got %d", len(loadedLogFile))
 	}
 
 	expectedValue := "ONFF-0258"
@@ -451,11 +465,16 @@ func TestLoadFile_redefining_myWWFF_must_fail(t *testing.T) {
 		t.Errorf("Not the expected MyWWFF value: %s (expecting %s)", loadedLogFile[0].MyWWFF, expectedValue)
 	}
 
+	expectedValue = "ONFF-0001"
+	if loadedLogFile[1].MyWWFF != expectedValue {
+		t.Errorf("Not the expected MyWWFF value in the new segment: %s (expecting %s)", loadedLogFile[1].MyWWFF, expectedValue)
+	}
+
 	//Clean Up
 	os.Remove(temporaryDataFileName)
 }
 
-func TestLoadFile_redefining_mySOTA_must_fail(t *testing.T) {
+func TestLoadFile_redefining_mySOTA_starts_new_segment(t *testing.T) {
 
 	//Given
 	dataArray := make([]string, 0)
@@ -470,6 +489,7 @@ func TestLoadFile_redefining_mySOTA_must_fail(t *testing.T) {
 	dataArray = append(dataArray, "20/5/23")
 	dataArray = append(dataArray, "40m cw 0950 ik5zve/5 9 5")
 	dataArray = append(dataArray, "mySota on/on-111")
+	dataArray = append(dataArray, "0955 on6zq")
 
 	temporaryDataFileName := createTestFile(dataArray)
 
@@ -477,11 +497,11 @@ func TestLoadFile_redefining_mySOTA_must_fail(t *testing.T) {
 	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
 
 	//Then
-	if isLoadedOK {
-		t.Error("Test file processing should return with an error")
+	if !isLoadedOK {
+		t.Error("Test file could not be correctly processed")
 	}
-	if len(loadedLogFile) == 0 {
-		t.Error("No data loaded")
+	if len(loadedLogFile) != 2 {
+		t.Fatalf("Expected 2 QSOs, got %d", len(loadedLogFile))
 	}
 
 	expectedValue := "ON/ON-001"
@@ -489,11 +509,16 @@ func TestLoadFile_redefining_mySOTA_must_fail(t *testing.T) {
 		t.Errorf("Not the expected MySOTA value: %s (expecting %s)", loadedLogFile[0].MySOTA, expectedValue)
 	}
 
+	expectedValue = "ON/ON-111"
+	if loadedLogFile[1].MySOTA != expectedValue {
+		t.Errorf("Not the expected MySOTA value in the new segment: %s (expecting %s)", loadedLogFile[1].MySOTA, expectedValue)
+	}
+
 	//Clean Up
 	os.Remove(temporaryDataFileName)
 }
 
-func TestLoadFile_redefining_myGRID_must_fail(t *testing.T) {
+func TestLoadFile_redefining_myGRID_starts_new_segment(t *testing.T) {
 
 	//Given
 	dataArray := make([]string, 0)
@@ -509,6 +534,7 @@ func TestLoadFile_redefining_myGRID_must_fail(t *testing.T) {
 	dataArray = append(dataArray, "20/5/23")
 	dataArray = append(dataArray, "40m cw 0950 ik5zve/5 9 5")
 	dataArray = append(dataArray, "myGrid ZZ99")
+	dataArray = append(dataArray, "0955 on6zq")
 
 	temporaryDataFileName := createTestFile(dataArray)
 
@@ -516,11 +542,11 @@ func TestLoadFile_redefining_myGRID_must_fail(t *testing.T) {
 	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
 
 	//Then
-	if isLoadedOK {
-		t.Error("Test file processing should return with an error")
+	if !isLoadedOK {
+		t.Error("Test file could not be correctly processed")
 	}
-	if len(loadedLogFile) == 0 {
-		t.Error("No data loaded")
+	if len(loadedLogFile) != 2 {
+		t.Fatalf("Expected 2 QSOs, got %d", len(loadedLogFile))
 	}
 
 	expectedValue := "YO50"
@@ -528,11 +554,16 @@ func TestLoadFile_redefining_myGRID_must_fail(t *testing.T) {
 		t.Errorf("Not the expected MyGRID value: %s (expecting %s)", loadedLogFile[0].MyGrid, expectedValue)
 	}
 
+	expectedValue = "ZZ99"
+	if loadedLogFile[1].MyGrid != expectedValue {
+		t.Errorf("Not the expected MyGRID value in the new segment: %s (expecting %s)", loadedLogFile[1].MyGrid, expectedValue)
+	}
+
 	//Clean Up
 	os.Remove(temporaryDataFileName)
 }
 
-func TestLoadFile_redefining_operator_must_fail(t *testing.T) {
+func TestLoadFile_redefining_operator_starts_new_segment(t *testing.T) {
 
 	//Given
 	dataArray := make([]string, 0)
@@ -546,7 +577,8 @@ func TestLoadFile_redefining_operator_must_fail(t *testing.T) {
 	dataArray = append(dataArray, " #Log")
 	dataArray = append(dataArray, "20/5/23")
 	dataArray = append(dataArray, "40m cw 0950 ik5zve/5 9 5")
-	dataArray = append(dataArray, "operator blahh")
+	dataArray = append(dataArray, "operator on4do")
+	dataArray = append(dataArray, "0955 on6zq")
 
 	temporaryDataFileName := createTestFile(dataArray)
 
@@ -554,11 +586,11 @@ func TestLoadFile_redefining_operator_must_fail(t *testing.T) {
 	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
 
 	//Then
-	if isLoadedOK {
-		t.Error("Test file processing should return with an error")
+	if !isLoadedOK {
+		t.Error("Test file could not be correctly processed")
 	}
-	if len(loadedLogFile) == 0 {
-		t.Error("No data loaded")
+	if len(loadedLogFile) != 2 {
+		t.Fatalf("Expected 2 QSOs, got %d", len(loadedLogFile))
 	}
 
 	expectedValue := "ON4KJM"
@@ -566,11 +598,16 @@ func TestLoadFile_redefining_operator_must_fail(t *testing.T) {
 		t.Errorf("Not the expected operator value: %s (expecting %s)", loadedLogFile[0].Operator, expectedValue)
 	}
 
+	expectedValue = "ON4DO"
+	if loadedLogFile[1].Operator != expectedValue {
+		t.Errorf("Not the expected operator value in the new segment: %s (expecting %s)", loadedLogFile[1].Operator, expectedValue)
+	}
+
 	//Clean Up
 	os.Remove(temporaryDataFileName)
 }
 
-func TestLoadFile_redefining_nickname_must_fail(t *testing.T) {
+func TestLoadFile_redefining_nickname_starts_new_segment(t *testing.T) {
 
 	//Given
 	dataArray := make([]string, 0)
@@ -585,6 +622,46 @@ func TestLoadFile_redefining_nickname_must_fail(t *testing.T) {
 	dataArray = append(dataArray, "20/5/23")
 	dataArray = append(dataArray, "40m cw 0950 ik5zve/5 9 5")
 	dataArray = append(dataArray, "nickname blaaahh")
+	dataArray = append(dataArray, "0955 on6zq")
+
+	temporaryDataFileName := createTestFile(dataArray)
+
+	//When
+	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
+
+	//Then
+	if !isLoadedOK {
+		t.Error("Test file could not be correctly processed")
+	}
+	if len(loadedLogFile) != 2 {
+		t.Fatalf("Expected 2 QSOs, got %d", len(loadedLogFile))
+	}
+
+	expectedValue := "Portable"
+	if loadedLogFile[0].Nickname != expectedValue {
+		t.Errorf("Not the expected Nickname value: %s (expecting %s)", loadedLogFile[0].Nickname, expectedValue)
+	}
+
+	expectedValue = "blaaahh"
+	if loadedLogFile[1].Nickname != expectedValue {
+		t.Errorf("Not the expected Nickname value in the new segment: %s (expecting %s)", loadedLogFile[1].Nickname, expectedValue)
+	}
+
+	//Clean Up
+	os.Remove(temporaryDataFileName)
+}
+
+func TestLoadFile_redefining_in_header_must_fail(t *testing.T) {
+
+	//Given
+	dataArray := make([]string, 0)
+	dataArray = append(dataArray, "# Header")
+	dataArray = append(dataArray, "myCall on4kjm/p")
+	dataArray = append(dataArray, "myWwff onff-0258")
+	dataArray = append(dataArray, "myWwff onff-0001")
+	dataArray = append(dataArray, " #Log")
+	dataArray = append(dataArray, "20/5/23")
+	dataArray = append(dataArray, "40m cw 0950 ik5zve/5 9 5")
 
 	temporaryDataFileName := createTestFile(dataArray)
 
@@ -599,9 +676,9 @@ func TestLoadFile_redefining_nickname_must_fail(t *testing.T) {
 		t.Error("No data loaded")
 	}
 
-	expectedValue := "Portable"
-	if loadedLogFile[0].Nickname != expectedValue {
-		t.Errorf("Not the expected Nickname value: %s (expecting %s)", loadedLogFile[0].Nickname, expectedValue)
+	expectedValue := "ONFF-0258"
+	if loadedLogFile[0].MyWWFF != expectedValue {
+		t.Errorf("Not the expected MyWWFF value: %s (expecting %s)", loadedLogFile[0].MyWWFF, expectedValue)
 	}
 
 	//Clean Up
@@ -718,7 +795,7 @@ func TestLoadFile_wrongHeader(t *testing.T) {
 		t.Error("No data loaded")
 	}
 
-	expectedValue := "*FOOBAR"
+	expectedValue := "FOOBAR"
 	if loadedLogFile[0].MyCall != expectedValue {
 		t.Errorf("Not the expected MyCall value: %s (expecting %s)", loadedLogFile[0].MyCall, expectedValue)
 	}
@@ -726,7 +803,7 @@ func TestLoadFile_wrongHeader(t *testing.T) {
 	if loadedLogFile[0].Operator != expectedValue {
 		t.Errorf("Not the expected Operator value: %s (expecting %s)", loadedLogFile[0].Operator, expectedValue)
 	}
-	expectedValue = "*FOOBAR"
+	expectedValue = "FOOBAR"
 	if loadedLogFile[0].MyWWFF != expectedValue {
 		t.Errorf("Not the expected MyWWFF value: %s (expecting %s)", loadedLogFile[0].MyWWFF, expectedValue)
 	}
@@ -739,6 +816,10 @@ func TestLoadFile_wrongHeader(t *testing.T) {
 	if loadedLogFile[0].Call != expectedValue {
 		t.Errorf("Not the expected Call[0] value: %s (expecting %s)", loadedLogFile[0].Call, expectedValue)
 	}
+	expectedInvalidFields := []LogField{FieldMyCall, FieldMyWWFF}
+	if !reflect.DeepEqual(loadedLogFile[0].InvalidFields.Fields(), expectedInvalidFields) {
+		t.Errorf("Not the expected invalid fields[0]: %v (expecting %v)", loadedLogFile[0].InvalidFields.Fields(), expectedInvalidFields)
+	}
 	expectedValue = "0950"
 	if loadedLogFile[0].Time != expectedValue {
 		t.Errorf("Not the expected Time[0] value: %s (expecting %s)", loadedLogFile[0].Time, expectedValue)
@@ -1036,23 +1117,23 @@ func TestLoadFile_wrongData(t *testing.T) {
 		t.Error("No data loaded")
 	}
 
-	expectedValue := "*FOOBAR"
+	expectedValue := "FOOBAR"
 	if loadedLogFile[0].MyCall != expectedValue {
 		t.Errorf("Not the expected MyCall value: %s (expecting %s)", loadedLogFile[0].MyCall, expectedValue)
 	}
-	expectedValue = "*FOOBAR"
+	expectedValue = "FOOBAR"
 	if loadedLogFile[0].Operator != expectedValue {
 		t.Errorf("Not the expected Operator value: %s (expecting %s)", loadedLogFile[0].Operator, expectedValue)
 	}
-	expectedValue = "*FOOBAR"
+	expectedValue = "FOOBAR"
 	if loadedLogFile[0].MyWWFF != expectedValue {
 		t.Errorf("Not the expected MyWWFF value: %s (expecting %s)", loadedLogFile[0].MyWWFF, expectedValue)
 	}
-	expectedValue = "*FOOBAR"
+	expectedValue = "FOOBAR"
 	if loadedLogFile[0].MySOTA != expectedValue {
 		t.Errorf("Not the expected MySOTA value: %s (expecting %s)", loadedLogFile[0].MySOTA, expectedValue)
 	}
-	expectedValue = "*foobar"
+	expectedValue = "foobar"
 	if loadedLogFile[0].MyGrid != expectedValue {
 		t.Errorf("Not the expected MyGrid value: %s (expecting %s)", loadedLogFile[0].MyGrid, expectedValue)
 	}
@@ -1061,6 +1142,10 @@ func TestLoadFile_wrongData(t *testing.T) {
 	if loadedLogFile[0].Call != expectedValue {
 		t.Errorf("Not the expected Call[0] value: %s (expecting %s)", loadedLogFile[0].Call, expectedValue)
 	}
+	expectedInvalidFields := []LogField{FieldMyCall, FieldOperator, FieldMyWWFF, FieldMySOTA, FieldMyGrid}
+	if !reflect.DeepEqual(loadedLogFile[0].InvalidFields.Fields(), expectedInvalidFields) {
+		t.Errorf("Not the expected invalid fields[0]: %v (expecting %v)", loadedLogFile[0].InvalidFields.Fields(), expectedInvalidFields)
+	}
 	expectedValue = "0950"
 	if loadedLogFile[0].Time != expectedValue {
 		t.Errorf("Not the expected Time[0] value: %s (expecting %s)", loadedLogFile[0].Time, expectedValue)
@@ -1118,27 +1203,31 @@ func TestLoadFile_firstCallWrong(t *testing.T) {
 		t.Error("No data loaded")
 	}
 
-	expectedValue := "*FOOBAR"
+	expectedValue := "FOOBAR"
 	if loadedLogFile[0].MyCall != expectedValue {
 		t.Errorf("Not the expected MyCall value: %s (expecting %s)", loadedLogFile[0].MyCall, expectedValue)
 	}
-	expectedValue = "*FOOBAR"
+	expectedValue = "FOOBAR"
 	if loadedLogFile[0].Operator != expectedValue {
 		t.Errorf("Not the expected Operator value: %s (expecting %s)", loadedLogFile[0].Operator, expectedValue)
 	}
-	expectedValue = "*FOOBAR"
+	expectedValue = "FOOBAR"
 	if loadedLogFile[0].MyWWFF != expectedValue {
 		t.Errorf("Not the expected MyWWFF value: %s (expecting %s)", loadedLogFile[0].MyWWFF, expectedValue)
 	}
-	expectedValue = "*FOOBAR"
+	expectedValue = "FOOBAR"
 	if loadedLogFile[0].MySOTA != expectedValue {
 		t.Errorf("Not the expected MySOTA value: %s (expecting %s)", loadedLogFile[0].MySOTA, expectedValue)
 	}
 
-	expectedValue = "*ON4KJM/P/QRP"
+	expectedValue = "ON4KJM/P/QRP"
 	if loadedLogFile[0].Call != expectedValue {
 		t.Errorf("Not the expected Call[0] value: %s (expecting %s)", loadedLogFile[0].Call, expectedValue)
 	}
+	expectedInvalidFields := []LogField{FieldMyCall, FieldOperator, FieldMyWWFF, FieldMySOTA, FieldCall}
+	if !reflect.DeepEqual(loadedLogFile[0].InvalidFields.Fields(), expectedInvalidFields) {
+		t.Errorf("Not the expected invalid fields[0]: %v (expecting %v)", loadedLogFile[0].InvalidFields.Fields(), expectedInvalidFields)
+	}
 	expectedValue = "0950"
 	if loadedLogFile[0].Time != expectedValue {
 		t.Errorf("Not the expected Time[0] value: %s (expecting %s)", loadedLogFile[0].Time, expectedValue)
@@ -1167,6 +1256,308 @@ func TestLoadFile_firstCallWrong(t *testing.T) {
 	os.Remove(temporaryDataFileName)
 }
 
+func TestLoadFile_consecutiveSerialNumbers(t *testing.T) {
+
+	//Given
+	dataArray := make([]string, 0)
+	dataArray = append(dataArray, "# Header")
+	dataArray = append(dataArray, "myCall wb9zzz")
+	dataArray = append(dataArray, "serial 1 band")
+	dataArray = append(dataArray, " #Log")
+	dataArray = append(dataArray, "date 2016-01-02")
+	dataArray = append(dataArray, "rtty 15m")
+	dataArray = append(dataArray, "1839 wy7fd .wy")
+	dataArray = append(dataArray, "20m")
+	dataArray = append(dataArray, "1856 ku1t ,33 .wv")
+	dataArray = append(dataArray, "     w4nf .va")
+	dataArray = append(dataArray, "15m")
+	dataArray = append(dataArray, "1901 n4zz .tn")
+
+	temporaryDataFileName := createTestFile(dataArray)
+
+	//When
+	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
+
+	//Then
+	if !isLoadedOK {
+		t.Error("Test file could not be correctly processed")
+	}
+	if len(loadedLogFile) != 4 {
+		t.Fatalf("Expected 4 QSOs, got %d", len(loadedLogFile))
+	}
+
+	expectedSerials := []string{"1", "33", "34", "2"}
+	for i, expectedValue := range expectedSerials {
+		if loadedLogFile[i].STX != expectedValue {
+			t.Errorf("Not the expected STX[%d] value: %s (expecting %s)", i, loadedLogFile[i].STX, expectedValue)
+		}
+	}
+
+	//Clean Up
+	os.Remove(temporaryDataFileName)
+}
+
+func TestLoadLog_defaultSerialStart(t *testing.T) {
+	tests := []struct {
+		name        string
+		definition  string
+		wantSerials []string
+	}{
+		{"serial", "serial", []string{"1", "2", "3"}},
+		{"serial band", "serial band", []string{"1", "1", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//Given
+			input := strings.Join([]string{
+				"mycall wb9zzz",
+				tt.definition,
+				"date 2016-01-02 rtty",
+				"15m 1839 wy7fd",
+				"20m 1856 ku1t",
+				"1857 w4nf",
+			}, "\n")
+
+			//When
+			loadedLogFile, diagnostics := LoadLog(strings.NewReader(input), false)
+
+			//Then
+			if len(diagnostics) != 0 {
+				t.Errorf("LoadLog() unexpected diagnostics: %v", diagnostics)
+			}
+			var gotSerials []string
+			for _, logLine := range loadedLogFile {
+				gotSerials = append(gotSerials, logLine.STX)
+			}
+			if !reflect.DeepEqual(gotSerials, tt.wantSerials) {
+				t.Errorf("LoadLog() serials = %v, want %v", gotSerials, tt.wantSerials)
+			}
+		})
+	}
+}
+
+func TestLoadFile_invalidSerialDefinition(t *testing.T) {
+
+	//Given
+	dataArray := make([]string, 0)
+	dataArray = append(dataArray, "myCall wb9zzz")
+	dataArray = append(dataArray, "serial one")
+	dataArray = append(dataArray, "date 2016-01-02")
+	dataArray = append(dataArray, "rtty 15m")
+	dataArray = append(dataArray, "1839 wy7fd .wy")
+
+	temporaryDataFileName := createTestFile(dataArray)
+
+	//When
+	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
+
+	//Then
+	if isLoadedOK {
+		t.Error("Test file processing should return with an error")
+	}
+	if loadedLogFile[0].STX != "" {
+		t.Errorf("Not the expected STX value: %s (expecting nothing)", loadedLogFile[0].STX)
+	}
+
+	//Clean Up
+	os.Remove(temporaryDataFileName)
+}
+
+func TestLoadFile_multipleReferences(t *testing.T) {
+
+	//Given
+	dataArray := make([]string, 0)
+	dataArray = append(dataArray, "myCall on4kjm/p")
+	dataArray = append(dataArray, "myWwff onff-0258, onff-0259")
+	dataArray = append(dataArray, "myPota on-00258 on-00259")
+	dataArray = append(dataArray, "date 2020-05-23")
+	dataArray = append(dataArray, "40m cw 0950 ik5zve/5 9 5")
+
+	temporaryDataFileName := createTestFile(dataArray)
+
+	//When
+	loadedLogFile, isLoadedOK := LoadFile(temporaryDataFileName, true)
+
+	//Then
+	if !isLoadedOK {
+		t.Error("Test file could not be correctly processed")
+	}
+	expectedValue := "ONFF-0258,ONFF-0259"
+	if loadedLogFile[0].MyWWFF != expectedValue {
+		t.Errorf("Not the expected MyWWFF value: %s (expecting %s)", loadedLogFile[0].MyWWFF, expectedValue)
+	}
+	expectedValue = "ON-00258,ON-00259"
+	if loadedLogFile[0].MyPOTA != expectedValue {
+		t.Errorf("Not the expected MyPOTA value: %s (expecting %s)", loadedLogFile[0].MyPOTA, expectedValue)
+	}
+
+	//Clean Up
+	os.Remove(temporaryDataFileName)
+}
+
+func TestLoadLog_diagnostics(t *testing.T) {
+	//Given
+	input := strings.Join([]string{
+		"mycall on4kjm/p",
+		"mycall on4kjm",
+		"date 2020-05-23",
+		"40m cw 0950 ik5zve/5 9 5",
+		"on6zq",
+	}, "\n")
+
+	//When
+	loadedLogFile, diagnostics := LoadLog(strings.NewReader(input), false)
+
+	//Then
+	if len(loadedLogFile) != 2 {
+		t.Errorf("Expected 2 QSOs, got %d", len(loadedLogFile))
+	}
+	expectedDiagnostics := []Diagnostic{
+		{Line: 2, Code: CodeHeaderRedefinition, Severity: SeverityError, Message: "Attempt to redefine MyCall"},
+	}
+	if !reflect.DeepEqual(diagnostics, expectedDiagnostics) {
+		t.Errorf("LoadLog() diagnostics = %v, want %v", diagnostics, expectedDiagnostics)
+	}
+}
+
+func TestLoadLog_region(t *testing.T) {
+	//Given
+	input := strings.Join([]string{
+		"mycall w1aw",
+		"region 2",
+		"region 1",
+		"date 2020-05-23",
+		"40m 7.250 ssb 0950 ik5zve/5",
+		"4m on6zq",
+	}, "\n")
+
+	//When
+	loadedLogFile, diagnostics := LoadLog(strings.NewReader(input), false)
+
+	//Then
+	if len(loadedLogFile) != 2 {
+		t.Fatalf("Expected 2 QSOs, got %d", len(loadedLogFile))
+	}
+	if loadedLogFile[0].Frequency != "7.250" || loadedLogFile[0].Region != "2" {
+		t.Errorf("Not the expected frequency and region: %s, %s (expecting 7.250, 2)", loadedLogFile[0].Frequency, loadedLogFile[0].Region)
+	}
+	expectedDiagnostics := []Diagnostic{
+		{Line: 3, Code: CodeHeaderRedefinition, Severity: SeverityError, Message: "Attempt to redefine Region"},
+		{Line: 6, Column: 1, Length: 2, Code: CodeInvalidBand, Severity: SeverityError, Message: "The 4m band is not allocated in IARU region 2."},
+	}
+	if !reflect.DeepEqual(diagnostics, expectedDiagnostics) {
+		t.Errorf("LoadLog() diagnostics = %v, want %v", diagnostics, expectedDiagnostics)
+	}
+}
+
+func TestLoadLog_bandFromFrequency(t *testing.T) {
+	//Given
+	input := strings.Join([]string{
+		"mycall on4kjm",
+		"date 2020-05-23 cw",
+		"7.018 0950 ik5zve/5",
+		"7.025 on6zq",
+		"14.062 dl1aa",
+	}, "\n")
+
+	//When
+	loadedLogFile, diagnostics := LoadLog(strings.NewReader(input), false)
+
+	//Then
+	//Once the band is derived from the first frequency, the following ones must be in that band
+	expectedBands := []string{"40m@7.018", "40m@7.025", "40m@"}
+	var gotBands []string
+	for _, logLine := range loadedLogFile {
+		gotBands = append(gotBands, logLine.Band+"@"+logLine.Frequency)
+	}
+	if !reflect.DeepEqual(gotBands, expectedBands) {
+		t.Errorf("LoadLog() bands = %v, want %v", gotBands, expectedBands)
+	}
+	expectedDiagnostics := []Diagnostic{
+		{Line: 5, Column: 1, Length: 6, Code: CodeInvalidFrequency, Severity: SeverityError, Message: "Frequency [14.062] is invalid for 40m band."},
+	}
+	if !reflect.DeepEqual(diagnostics, expectedDiagnostics) {
+		t.Errorf("LoadLog() diagnostics = %v, want %v", diagnostics, expectedDiagnostics)
+	}
+}
+
+func TestLoadLog_invalidRegion(t *testing.T) {
+	//Given
+	input := strings.Join([]string{
+		"mycall on4kjm",
+		"region 4",
+		"date 2020-05-23",
+		"40m ssb 0950 ik5zve/5",
+		"region 1",
+	}, "\n")
+
+	//When
+	_, diagnostics := LoadLog(strings.NewReader(input), false)
+
+	//Then
+	expectedDiagnostics := []Diagnostic{
+		{Line: 2, Column: 8, Length: 1, Code: CodeInvalidRegion, Severity: SeverityError, Message: "Invalid \"Region\": 4 ([4] is not a valid IARU region (1, 2 or 3))"},
+		{Line: 5, Code: CodeInvalidRegion, Severity: SeverityError, Message: "The region must be defined before the first band"},
+	}
+	if !reflect.DeepEqual(diagnostics, expectedDiagnostics) {
+		t.Errorf("LoadLog() diagnostics = %v, want %v", diagnostics, expectedDiagnostics)
+	}
+}
+
+func TestLoadLog_diagnosticLocation(t *testing.T) {
+	//Given
+	input := strings.Join([]string{
+		"mycall  on4kjm/p",
+		"mysota  on/xx-1",
+		"date 2020-05-23",
+		"40m cw 0950 ik5zve/5 <a comment> 9 5 xyz",
+	}, "\n")
+
+	//When
+	_, diagnostics := LoadLog(strings.NewReader(input), false)
+
+	//Then
+	expectedDiagnostics := []Diagnostic{
+		{Line: 2, Column: 9, Length: 7, Code: CodeInvalidSota, Severity: SeverityError, Message: "Invalid \"My SOTA\": on/xx-1 ([ON/XX-1] is an invalid SOTA reference)"},
+		{Line: 4, Column: 38, Length: 3, Code: CodeUnknownElement, Severity: SeverityError, Message: "Unable to make sense of [xyz]."},
+	}
+	if !reflect.DeepEqual(diagnostics, expectedDiagnostics) {
+		t.Errorf("LoadLog() diagnostics = %#v, want %#v", diagnostics, expectedDiagnostics)
+	}
+}
+
+func TestLoadLog_interpolationError(t *testing.T) {
+	//Given
+	input := strings.Join([]string{
+		"mycall on4kjm/p",
+		"date 2020-05-23",
+		"40m cw 0950 ik5zve/5 9 5",
+		"on6zq",
+	}, "\n")
+
+	//When
+	_, diagnostics := LoadLog(strings.NewReader(input), true)
+
+	//Then
+	expectedDiagnostics := []Diagnostic{
+		{Line: 0, Code: CodeTimeInterpolation, Severity: SeverityFatal, Message: "missing new time to infer time"},
+	}
+	if !reflect.DeepEqual(diagnostics, expectedDiagnostics) {
+		t.Errorf("LoadLog() diagnostics = %v, want %v", diagnostics, expectedDiagnostics)
+	}
+}
+
+func TestLoadLogFile_missingFile(t *testing.T) {
+	loadedLogFile, diagnostics := LoadLogFile("../test/data/doesNotExist.txt", false)
+
+	if loadedLogFile != nil {
+		t.Errorf("Expected no QSO, got %d", len(loadedLogFile))
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Severity != SeverityFatal {
+		t.Errorf("Expected a single fatal diagnostic, got %v", diagnostics)
+	}
+}
+
 //createTestFile creates and populates a test FLE input file.
 //Returns the created temporary filename.
 func createTestFile(dataArray []string) (tempFileName string) {
@@ -0,0 +1,61 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"encoding/json"
+)
+
+//LoadReport is the machine readable result of loading a FLE log
+type LoadReport struct {
+	InputFilename string       `json:"inputFile"`
+	IsValid       bool         `json:"valid"`
+	QsoCount      int          `json:"qsoCount"`
+	Qsos          []LogLine    `json:"qsos"`
+	Diagnostics   []Diagnostic `json:"diagnostics"` //errors and warnings
+}
+
+//BuildLoadReport assembles the report of a loaded log. The log is valid if no error was found.
+func BuildLoadReport(inputFilename string, fullLog []LogLine, diagnostics []Diagnostic) LoadReport {
+	//Empty lists are reported as such (and not as null)
+	if fullLog == nil {
+		fullLog = []LogLine{}
+	}
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	return LoadReport{
+		InputFilename: inputFilename,
+		IsValid:       !HasErrors(diagnostics),
+		QsoCount:      len(fullLog),
+		Qsos:          fullLog,
+		Diagnostics:   diagnostics,
+	}
+}
+
+//LoadFileAsJSON loads and validates a FLE log file and returns the JSON report of it.
+//Nothing else is printed. isProcessedOK is false if errors were found.
+func LoadFileAsJSON(inputFilename string, isInterpolateTime bool) (jsonReport string, isProcessedOK bool, err error) {
+	fullLog, diagnostics := LoadLogFile(inputFilename, isInterpolateTime)
+	report := BuildLoadReport(inputFilename, fullLog, diagnostics)
+
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", false, err
+	}
+	return string(output) + "\n", report.IsValid, nil
+}
@@ -0,0 +1,130 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBuildLoadReport(t *testing.T) {
+	type args struct {
+		fullLog     []LogLine
+		diagnostics []Diagnostic
+	}
+	tests := []struct {
+		name string
+		args args
+		want LoadReport
+	}{
+		{
+			"Nothing loaded",
+			args{fullLog: nil, diagnostics: nil},
+			LoadReport{InputFilename: "test.txt", IsValid: true, QsoCount: 0, Qsos: []LogLine{}, Diagnostics: []Diagnostic{}},
+		},
+		{
+			"Only warnings",
+			args{fullLog: []LogLine{{Call: "on4do"}}, diagnostics: []Diagnostic{{Line: 3, Severity: SeverityWarning, Message: "odd"}}},
+			LoadReport{InputFilename: "test.txt", IsValid: true, QsoCount: 1, Qsos: []LogLine{{Call: "on4do"}}, Diagnostics: []Diagnostic{{Line: 3, Severity: SeverityWarning, Message: "odd"}}},
+		},
+		{
+			"Errors",
+			args{fullLog: []LogLine{{Call: "on4do"}}, diagnostics: []Diagnostic{{Line: 3, Severity: SeverityError, Message: "bad"}}},
+			LoadReport{InputFilename: "test.txt", IsValid: false, QsoCount: 1, Qsos: []LogLine{{Call: "on4do"}}, Diagnostics: []Diagnostic{{Line: 3, Severity: SeverityError, Message: "bad"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildLoadReport("test.txt", tt.args.fullLog, tt.args.diagnostics); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildLoadReport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadReport_json(t *testing.T) {
+	report := BuildLoadReport("test.txt",
+		[]LogLine{{Date: "2020-05-23", Call: "ON4DO", Time: "0954"}},
+		[]Diagnostic{{Line: 4, Column: 10, Length: 3, Code: CodeInvalidReport, Severity: SeverityError, Message: "Invalid report"}})
+
+	got, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	want := `{"inputFile":"test.txt","valid":false,"qsoCount":1,` +
+		`"qsos":[{"date":"2020-05-23","time":"0954","call":"ON4DO"}],` +
+		`"diagnostics":[{"line":4,"column":10,"length":3,"code":"invalid-report","severity":"error","message":"Invalid report"}]}`
+	if string(got) != want {
+		t.Errorf("json.Marshal(LoadReport) = %s, want %s", got, want)
+	}
+}
+
+func TestLoadFileAsJSON(t *testing.T) {
+	tests := []struct {
+		name            string
+		dataArray       []string
+		wantProcessedOK bool
+		wantQsoCount    int
+		wantErrorCodes  []DiagnosticCode
+	}{
+		{
+			"Valid log",
+			[]string{"mycall on4kjm/p", "date 2020-05-23", "40m cw 0950 ik5zve/5 9 5", "0954 on4do"},
+			true, 2, []DiagnosticCode{},
+		},
+		{
+			"Invalid log",
+			[]string{"mycall on4kjm/p", "mycall on4kjm", "date 2020-05-23", "40m cw 0950 ik5zve/5 9 5"},
+			false, 1, []DiagnosticCode{CodeHeaderRedefinition},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temporaryDataFileName := createTestFile(tt.dataArray)
+			defer os.Remove(temporaryDataFileName)
+
+			jsonReport, isProcessedOK, err := LoadFileAsJSON(temporaryDataFileName, false)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if isProcessedOK != tt.wantProcessedOK {
+				t.Errorf("LoadFileAsJSON() isProcessedOK = %v, want %v", isProcessedOK, tt.wantProcessedOK)
+			}
+
+			var report struct {
+				Valid       bool
+				QsoCount    int
+				Diagnostics []struct{ Code DiagnosticCode }
+			}
+			if err := json.Unmarshal([]byte(jsonReport), &report); err != nil {
+				t.Fatalf("Unable to read back the report: %s\n%s", err, jsonReport)
+			}
+			if report.Valid != tt.wantProcessedOK || report.QsoCount != tt.wantQsoCount {
+				t.Errorf("Unexpected report %s", jsonReport)
+			}
+			gotCodes := []DiagnosticCode{}
+			for _, e := range report.Diagnostics {
+				gotCodes = append(gotCodes, e.Code)
+			}
+			if !reflect.DeepEqual(gotCodes, tt.wantErrorCodes) {
+				t.Errorf("LoadFileAsJSON() error codes = %v, want %v", gotCodes, tt.wantErrorCodes)
+			}
+		})
+	}
+}
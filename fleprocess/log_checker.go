@@ -0,0 +1,127 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"strings"
+)
+
+//logChecker checks that a log holds the data required by an output format, one QSO at a time.
+//The problems found are accumulated and reported by err once the whole log has been checked.
+type logChecker struct {
+	//checkSegmentHeader checks the header values (as found on the first line of a segment)
+	checkSegmentHeader func(logLine LogLine, location string) error
+	//checkLine returns the format specific problems of a log line (optional)
+	checkLine func(logLine LogLine, errorLocation string) []string
+
+	qsoCount        int
+	segmentCount    int
+	previousLogLine LogLine
+
+	//first segment with an invalid header, reported once the number of segments is known
+	invalidSegment           *LogLine
+	invalidSegmentIndex      int
+	invalidSegmentFirstEntry int
+
+	errorsBuffer strings.Builder
+	errorCount   int
+}
+
+//maxCheckerErrors is the maximum number of errors listed by a log checker: the following ones are only counted
+const maxCheckerErrors = 100
+
+//check records the problems of the next QSO of the log. It returns false if the QSO has a problem that prevents it from being written.
+func (checker *logChecker) check(logLine LogLine) bool {
+	//MyCall and the other header values are checked on the first line of each segment
+	if checker.qsoCount == 0 || !isSameHeader(checker.previousLogLine, logLine) {
+		if checker.invalidSegment == nil && checker.checkSegmentHeader(logLine, "") != nil {
+			invalidSegment := logLine
+			checker.invalidSegment = &invalidSegment
+			checker.invalidSegmentIndex = checker.segmentCount
+			checker.invalidSegmentFirstEntry = checker.qsoCount
+		}
+		checker.segmentCount++
+	}
+	checker.previousLogLine = logLine
+	checker.qsoCount++
+	previousErrorCount := checker.errorCount
+
+	//Compute the error location for a meaning full error
+	var errorLocation string
+	if logLine.Time == "" {
+		errorLocation = fmt.Sprintf("for log entry #%d", checker.qsoCount)
+	} else {
+		errorLocation = fmt.Sprintf("for log entry at %s (#%d)", logLine.Time, checker.qsoCount)
+	}
+
+	if logLine.Date == "" {
+		checker.addError("missing date %s", errorLocation)
+	}
+	if logLine.Band == "" {
+		checker.addError("missing band %s", errorLocation)
+	}
+	if logLine.Mode == "" {
+		checker.addError("missing mode %s", errorLocation)
+	}
+	if logLine.Call == "" {
+		checker.addError("missing call %s", errorLocation)
+	}
+	if logLine.Time == "" {
+		checker.addError("missing QSO time %s", errorLocation)
+	}
+	if checker.checkLine != nil {
+		for _, lineError := range checker.checkLine(logLine, errorLocation) {
+			checker.addError("%s", lineError)
+		}
+	}
+	for _, field := range invalidLineFields(logLine) {
+		checker.addError("invalid %s value %s", field, errorLocation)
+	}
+	return checker.errorCount == previousErrorCount
+}
+
+//addError accumulates an error message
+func (checker *logChecker) addError(format string, a ...interface{}) {
+	checker.errorCount++
+	if checker.errorCount > maxCheckerErrors {
+		return
+	}
+	if checker.errorsBuffer.Len() != 0 {
+		checker.errorsBuffer.WriteString(", ")
+	}
+	checker.errorsBuffer.WriteString(fmt.Sprintf(format, a...))
+}
+
+//err returns the problems found in the checked log. The segment header problems are reported first.
+func (checker *logChecker) err() error {
+	//do we have QSOs at all?
+	if checker.qsoCount == 0 {
+		return fmt.Errorf("No QSO found")
+	}
+	if checker.invalidSegment != nil {
+		location := segmentLocation(checker.segmentCount, checker.invalidSegmentIndex, checker.invalidSegmentFirstEntry)
+		return checker.checkSegmentHeader(*checker.invalidSegment, location)
+	}
+	if checker.errorCount > maxCheckerErrors {
+		return fmt.Errorf("%s (and %d more errors)", checker.errorsBuffer.String(), checker.errorCount-maxCheckerErrors)
+	}
+	if checker.errorsBuffer.Len() != 0 {
+		return fmt.Errorf(checker.errorsBuffer.String())
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_logChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		fullLog []LogLine
+		wantErr string
+	}{
+		{
+			"No QSO",
+			nil,
+			"No QSO found",
+		},
+		{
+			"Single segment",
+			[]LogLine{
+				{MyCall: "", Call: "S57LC", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW"},
+			},
+			"Missing MyCall",
+		},
+		{
+			"Segment located once the log is complete",
+			[]LogLine{
+				{MyCall: "ON4KJM/P", Call: "S57LC", Date: "2020-05-24", Time: "1310", Band: "20m", Mode: "CW"},
+				{MyCall: "ON4KJM/P", MySOTA: "ON/ON-001", Call: "ON4LY", Date: "2020-05-24", Band: "20m", Mode: "CW"},
+			},
+			"Missing MY-SOTA reference (segment #1 starting at log entry #1)",
+		},
+		{
+			"Line errors",
+			[]LogLine{
+				{MyCall: "ON4KJM/P", MySOTA: "ON/ON-001", Call: "S57LC", Date: "2020-05-24", Time: "1310", Mode: "CW"},
+				{MyCall: "ON4KJM/P", MySOTA: "ON/ON-001", Call: "ON4LY", Date: "2020-05-24", Band: "20m", Mode: "CW"},
+			},
+			"missing band for log entry at 1310 (#1), missing QSO time for log entry #2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := newAdifChecker(AdifParams{IsSOTAcli: true})
+			for _, logLine := range tt.fullLog {
+				checker.check(logLine)
+			}
+			err := checker.err()
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("logChecker.err() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_logChecker_manyErrors(t *testing.T) {
+	checker := newAdifChecker(AdifParams{IsSOTAcli: true})
+	for i := 0; i < maxCheckerErrors+5; i++ {
+		checker.check(LogLine{MyCall: "ON4KJM/P", MySOTA: "ON/ON-001", Call: "S57LC", Date: "2020-05-24", Band: "20m", Mode: "CW"})
+	}
+	err := checker.err()
+	if err == nil || !strings.HasSuffix(err.Error(), " (and 5 more errors)") {
+		t.Errorf("logChecker.err() = %v, want a summary of 5 more errors", err)
+	}
+	if got := strings.Count(err.Error(), "missing QSO time"); got != maxCheckerErrors {
+		t.Errorf("logChecker.err() lists %d errors, want %d", got, maxCheckerErrors)
+	}
+}
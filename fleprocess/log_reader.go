@@ -0,0 +1,356 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+//LogReader reads and validates a FLE log one QSO at a time, so that very large logs can be processed with bounded memory.
+//Only the QSOs of a time gap being interpolated are kept until the gap is closed.
+type LogReader struct {
+	parser            *Parser
+	isInterpolateTime bool
+
+	headerMyCall   string
+	headerOperator string
+	headerMyWWFF   string
+	headerMySOTA   string
+	headerMyPOTA   string
+	headerMyGrid   string
+	headerQslMsg   string
+	headerNickname string
+	headerSerial   SerialNumbers
+	headerRegion   string
+	//header fields holding an invalid value
+	headerInvalidFields FieldSet
+
+	previousLogLine LogLine
+	lineCount       int
+	qsoCount        int
+
+	//isInferTimeFatalError is set to true is something bad happened while storing time gaps.
+	isInferTimeFatalError bool
+	wrkTimeBlock          InferTimeBlock
+	//QSOs of the time gap being interpolated
+	gapQsos []LogLine
+	//QSOs ready to be returned
+	readyQsos  []LogLine
+	isEndOfLog bool
+
+	diagnostics []Diagnostic
+	//maximum number of diagnostics kept (0 for no limit): the following ones are only counted
+	diagnosticLimit    int
+	droppedDiagnostics int
+	droppedSeverity    Severity
+}
+
+//NewLogReader creates a reader loading the FLE log from the supplied input
+func NewLogReader(reader io.Reader, isInterpolateTime bool) *LogReader {
+	return &LogReader{parser: NewParser(reader), isInterpolateTime: isInterpolateTime}
+}
+
+//Next returns the next QSO of the log. isRead is false at the end of the log.
+//The QSOs are returned in the order of the log, once their time is known.
+func (logReader *LogReader) Next() (logLine LogLine, isRead bool) {
+	for len(logReader.readyQsos) == 0 {
+		if logReader.isEndOfLog {
+			return LogLine{}, false
+		}
+		logReader.readStatement()
+	}
+	logLine = logReader.readyQsos[0]
+	logReader.readyQsos = logReader.readyQsos[1:]
+	return logLine, true
+}
+
+//Diagnostics returns the problems found so far. The list is complete once Next has reached the end of the log.
+//If the number of diagnostics is limited, the last one summarizes the problems that are not listed.
+func (logReader *LogReader) Diagnostics() []Diagnostic {
+	if logReader.droppedDiagnostics == 0 {
+		return logReader.diagnostics
+	}
+	summary := Diagnostic{Code: CodeTooManyDiagnostics, Severity: logReader.droppedSeverity,
+		Message: fmt.Sprintf("%d more problems are not listed", logReader.droppedDiagnostics)}
+	return append(logReader.diagnostics[:len(logReader.diagnostics):len(logReader.diagnostics)], summary)
+}
+
+//limitDiagnostics sets the maximum number of diagnostics kept, so that a log full of errors can be read with bounded memory
+func (logReader *LogReader) limitDiagnostics(limit int) {
+	logReader.diagnosticLimit = limit
+}
+
+//storeDiagnostic records a problem, unless the maximum number of diagnostics is reached
+func (logReader *LogReader) storeDiagnostic(diagnostic Diagnostic) {
+	if logReader.diagnosticLimit > 0 && len(logReader.diagnostics) >= logReader.diagnosticLimit {
+		if logReader.droppedDiagnostics == 0 || diagnostic.Severity > logReader.droppedSeverity {
+			logReader.droppedSeverity = diagnostic.Severity
+		}
+		logReader.droppedDiagnostics++
+		return
+	}
+	logReader.diagnostics = append(logReader.diagnostics, diagnostic)
+}
+
+//Err returns the error encountered while reading the input, if any
+func (logReader *LogReader) Err() error {
+	return logReader.parser.Err()
+}
+
+//addDiagnostic records a problem found at the given line (0 if not related to a line)
+func (logReader *LogReader) addDiagnostic(line int, code DiagnosticCode, severity Severity, format string, a ...interface{}) {
+	logReader.storeDiagnostic(Diagnostic{Line: line, Code: code, Severity: severity, Message: fmt.Sprintf(format, a...)})
+}
+
+//addHeaderDiagnostic records a problem found in the value of a header keyword, pointing at that value
+func (logReader *LogReader) addHeaderDiagnostic(value Token, label string, diagnostic Diagnostic) {
+	trimmedValue := strings.TrimSpace(value.Text)
+	column := value.Column + strings.Index(value.Text, trimmedValue)
+	diagnostic = diagnostic.at(column, len(trimmedValue))
+	diagnostic.Line = value.Line
+	diagnostic.Message = fmt.Sprintf("Invalid %s: %s (%s)", label, trimmedValue, diagnostic.Message)
+	logReader.storeDiagnostic(diagnostic)
+}
+
+//isRedefinitionAllowed checks whether a header value can be (re)defined. A header value can't be redefined
+//in the header itself but it can be in the data block: this starts a new segment (for example a new
+//activation) and the following QSOs carry the new value.
+func (logReader *LogReader) isRedefinitionAllowed(keyword, currentValue string) bool {
+	if currentValue == "" {
+		return true
+	}
+	if logReader.qsoCount == 0 {
+		logReader.addDiagnostic(logReader.lineCount, CodeHeaderRedefinition, SeverityError, "Attempt to redefine %s", keyword)
+		return false
+	}
+	return true
+}
+
+//readStatement processes the next statement of the log
+func (logReader *LogReader) readStatement() {
+	statement, parseDiagnostics, isParsed := logReader.parser.Next()
+	if !isParsed {
+		logReader.endOfLog()
+		return
+	}
+	logReader.lineCount = statement.LineNumber()
+
+	switch typedStatement := statement.(type) {
+	case HeaderStatement:
+		logReader.processHeader(typedStatement)
+	case ContextStatement:
+		logReader.processDataLine(typedStatement.DataLine, parseDiagnostics)
+	case QsoStatement:
+		logReader.processDataLine(typedStatement.DataLine, parseDiagnostics)
+	}
+	//Comments, multi-line comments and empty lines are skipped
+}
+
+//processHeader stores the value of a header keyword. If there is no data after the keyword, the line is skipped.
+func (logReader *LogReader) processHeader(header HeaderStatement) {
+	value := header.Value
+	trimmedValue := strings.TrimSpace(value.Text)
+	var diagnostic *Diagnostic
+
+	switch header.Keyword {
+	case "mycall":
+		if logReader.isRedefinitionAllowed("MyCall", logReader.headerMyCall) && len(trimmedValue) > 0 {
+			logReader.headerMyCall, diagnostic = ValidateCall(trimmedValue)
+			logReader.headerInvalidFields = logReader.headerInvalidFields.with(FieldMyCall, diagnostic != nil)
+			if diagnostic != nil {
+				logReader.addHeaderDiagnostic(value, "myCall", *diagnostic)
+			}
+		}
+	case "operator":
+		if logReader.isRedefinitionAllowed("Operator", logReader.headerOperator) && len(trimmedValue) > 0 {
+			logReader.headerOperator, diagnostic = ValidateCall(trimmedValue)
+			logReader.headerInvalidFields = logReader.headerInvalidFields.with(FieldOperator, diagnostic != nil)
+			if diagnostic != nil {
+				logReader.addHeaderDiagnostic(value, "Operator", *diagnostic)
+			}
+		}
+	case "mywwff":
+		if logReader.isRedefinitionAllowed("MyWWFF", logReader.headerMyWWFF) && len(trimmedValue) > 0 {
+			logReader.headerMyWWFF, diagnostic = ValidateWwffList(value.Text)
+			logReader.headerInvalidFields = logReader.headerInvalidFields.with(FieldMyWWFF, diagnostic != nil)
+			if diagnostic != nil {
+				logReader.addHeaderDiagnostic(value, "\"My WWFF\"", *diagnostic)
+			}
+		}
+	case "mysota":
+		if logReader.isRedefinitionAllowed("MySOTA", logReader.headerMySOTA) && len(trimmedValue) > 0 {
+			logReader.headerMySOTA, diagnostic = ValidateSota(trimmedValue)
+			logReader.headerInvalidFields = logReader.headerInvalidFields.with(FieldMySOTA, diagnostic != nil)
+			if diagnostic != nil {
+				logReader.addHeaderDiagnostic(value, "\"My SOTA\"", *diagnostic)
+			}
+		}
+	case "mypota":
+		if logReader.isRedefinitionAllowed("MyPOTA", logReader.headerMyPOTA) && len(trimmedValue) > 0 {
+			logReader.headerMyPOTA, diagnostic = ValidatePotaList(value.Text)
+			logReader.headerInvalidFields = logReader.headerInvalidFields.with(FieldMyPOTA, diagnostic != nil)
+			if diagnostic != nil {
+				logReader.addHeaderDiagnostic(value, "\"My POTA\"", *diagnostic)
+			}
+		}
+	case "mygrid":
+		if logReader.isRedefinitionAllowed("MyGrid", logReader.headerMyGrid) && len(trimmedValue) > 0 {
+			logReader.headerMyGrid, diagnostic = ValidateGridLocator(trimmedValue)
+			logReader.headerInvalidFields = logReader.headerInvalidFields.with(FieldMyGrid, diagnostic != nil)
+			if diagnostic != nil {
+				logReader.addHeaderDiagnostic(value, "\"My Grid\"", *diagnostic)
+			}
+		}
+	case "qslmsg":
+		if len(value.Text) > 0 {
+			logReader.headerQslMsg = value.Text
+		}
+	case "nickname":
+		if logReader.isRedefinitionAllowed("eQSL Nickname", logReader.headerNickname) && len(trimmedValue) > 0 {
+			logReader.headerNickname = trimmedValue
+		}
+	case "serial":
+		//Attempt to redefine value
+		if logReader.headerSerial.isEnabled {
+			logReader.addDiagnostic(logReader.lineCount, CodeHeaderRedefinition, SeverityError, "Attempt to redefine Serial")
+		} else {
+			//The start number and the counter type are optional
+			errorMsg := ""
+			logReader.headerSerial, errorMsg = parseSerialDefinition(value.Text)
+			if len(errorMsg) != 0 {
+				logReader.addHeaderDiagnostic(value, "\"Serial\"", *newDiagnostic(CodeInvalidSerial, "%s", errorMsg))
+			}
+		}
+	case "region":
+		//The band plan applies to the whole log
+		if logReader.headerRegion != "" {
+			logReader.addDiagnostic(logReader.lineCount, CodeHeaderRedefinition, SeverityError, "Attempt to redefine Region")
+		} else if logReader.previousLogLine.Band != "" {
+			logReader.addDiagnostic(logReader.lineCount, CodeInvalidRegion, SeverityError, "The region must be defined before the first band")
+		} else if len(trimmedValue) > 0 {
+			logReader.headerRegion, diagnostic = ValidateRegion(trimmedValue)
+			if diagnostic != nil {
+				logReader.headerRegion = ""
+				logReader.addHeaderDiagnostic(value, "\"Region\"", *diagnostic)
+			}
+		}
+	}
+}
+
+//processDataLine evaluates a line of the data block. A line containing a call is a QSO.
+func (logReader *LogReader) processDataLine(dataLine DataLine, parseDiagnostics []Diagnostic) {
+	// Load the header values in the previousLogLine
+	previousLogLine := logReader.previousLogLine
+	previousLogLine.MyCall = logReader.headerMyCall
+	previousLogLine.Operator = logReader.headerOperator
+	previousLogLine.MyWWFF = logReader.headerMyWWFF
+	previousLogLine.MySOTA = logReader.headerMySOTA
+	previousLogLine.MyPOTA = logReader.headerMyPOTA
+	previousLogLine.MyGrid = logReader.headerMyGrid
+	previousLogLine.QSLmsg = logReader.headerQslMsg //previousLogLine.QslMsg is redundant
+	previousLogLine.Nickname = logReader.headerNickname
+	previousLogLine.Region = logReader.headerRegion
+	for _, field := range headerFields {
+		previousLogLine.InvalidFields = previousLogLine.InvalidFields.with(field, logReader.headerInvalidFields.Contains(field))
+	}
+
+	//evaluate the line
+	logline, lineDiagnostics := evaluateDataLine(dataLine, parseDiagnostics, previousLogLine)
+
+	//Store append the accumulated soft parsing errors into the global parsing error log file
+	for _, diagnostic := range lineDiagnostics {
+		diagnostic.Line = logReader.lineCount
+		logReader.storeDiagnostic(diagnostic)
+	}
+
+	//store the current logline so that it can be used as a model when parsing the next line
+	logReader.previousLogLine = logline
+
+	//we have a valid line (contains a call)
+	if logline.Call != "" {
+		//number the QSO if consecutive serial numbers are requested
+		logReader.headerSerial.assignSerial(&logline)
+		logReader.qsoCount++
+		logReader.storeQso(logline)
+	}
+}
+
+//storeQso queues the QSO. When interpolating the time, the QSOs without time are held until the end of the time gap.
+func (logReader *LogReader) storeQso(logline LogLine) {
+	if !logReader.isInterpolateTime || logReader.isInferTimeFatalError {
+		logReader.readyQsos = append(logReader.readyQsos, logline)
+		return
+	}
+
+	isEndOfGap, err := logReader.wrkTimeBlock.storeTimeGap(logline, logReader.qsoCount)
+	if err != nil {
+		logReader.addDiagnostic(logReader.lineCount, CodeTimeInterpolation, SeverityFatal, "%s", err)
+		logReader.isInferTimeFatalError = true
+	}
+	//If we reached the end of the time gap, we make the necessary checks and make our gap calculation
+	if isEndOfGap {
+		if err := logReader.wrkTimeBlock.finalizeTimeGap(); err != nil {
+			//If an error occured it is a fatal error: the processing of the log stops here
+			logReader.addDiagnostic(logReader.lineCount, CodeTimeInterpolation, SeverityFatal, "%s", err)
+			logReader.isInferTimeFatalError = true
+			logReader.releaseGapQsos()
+			logReader.readyQsos = append(logReader.readyQsos, logline)
+			logReader.isEndOfLog = true
+			return
+		}
+
+		for i := range logReader.gapQsos {
+			durationOffset := logReader.wrkTimeBlock.deltatime * time.Duration(i+1)
+			newTime := logReader.wrkTimeBlock.lastRecordedTime.Add(durationOffset)
+			logReader.gapQsos[i].Time = newTime.Format("1504")
+		}
+		logReader.releaseGapQsos()
+
+		//create a new block
+		logReader.wrkTimeBlock = InferTimeBlock{}
+
+		//Store this record in the new block as a new gap might be following
+		//no error or endOfGap processing as it has already been successfully processed
+		logReader.wrkTimeBlock.storeTimeGap(logline, logReader.qsoCount)
+	}
+
+	if logline.ActualTime == "" && !logReader.isInferTimeFatalError {
+		logReader.gapQsos = append(logReader.gapQsos, logline)
+		return
+	}
+	logReader.releaseGapQsos()
+	logReader.readyQsos = append(logReader.readyQsos, logline)
+}
+
+//releaseGapQsos makes the QSOs of the time gap available, with their time as it is
+func (logReader *LogReader) releaseGapQsos() {
+	logReader.readyQsos = append(logReader.readyQsos, logReader.gapQsos...)
+	logReader.gapQsos = nil
+}
+
+//endOfLog checks that the time gaps have all been closed and releases the remaining QSOs
+func (logReader *LogReader) endOfLog() {
+	//Do we have an open timeBlok that has not been closed.
+	if logReader.isInterpolateTime && (logReader.wrkTimeBlock.noTimeCount > 0) && (logReader.wrkTimeBlock.nextValidTime.IsZero()) {
+		logReader.addDiagnostic(0, CodeTimeInterpolation, SeverityFatal, "missing new time to infer time")
+	}
+	logReader.releaseGapQsos()
+	logReader.isEndOfLog = true
+}
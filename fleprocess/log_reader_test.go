@@ -0,0 +1,145 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLogReader_Next(t *testing.T) {
+	input := "mycall on4kjm/p\n" +
+		"date 2020-05-23 40m cw\n" +
+		"1200 on4aaa\n" +
+		"on4bbb\n" +
+		"on4ccc\n" +
+		"1203 on4ddd\n" +
+		"on4eee\n"
+
+	logReader := NewLogReader(strings.NewReader(input), true)
+
+	var gotTimes []string
+	for {
+		logLine, isRead := logReader.Next()
+		if !isRead {
+			break
+		}
+		gotTimes = append(gotTimes, logLine.Call+"@"+logLine.Time)
+		//the QSOs of a time gap are only held until the gap is closed
+		if len(logReader.gapQsos) > 2 {
+			t.Errorf("LogReader.Next() holds %d QSOs", len(logReader.gapQsos))
+		}
+	}
+
+	//The last QSO has no closing time: it is returned as it is
+	wantTimes := []string{"ON4AAA@1200", "ON4BBB@1201", "ON4CCC@1202", "ON4DDD@1203", "ON4EEE@1203"}
+	if !reflect.DeepEqual(gotTimes, wantTimes) {
+		t.Errorf("LogReader.Next() gotTimes = %v, want %v", gotTimes, wantTimes)
+	}
+	wantDiagnostics := []Diagnostic{{Line: 0, Code: CodeTimeInterpolation, Severity: SeverityFatal, Message: "missing new time to infer time"}}
+	if !reflect.DeepEqual(logReader.Diagnostics(), wantDiagnostics) {
+		t.Errorf("LogReader.Diagnostics() = %v, want %v", logReader.Diagnostics(), wantDiagnostics)
+	}
+	if err := logReader.Err(); err != nil {
+		t.Errorf("LogReader.Err() unexpected error: %v", err)
+	}
+}
+
+//generatedLog produces a FLE log of qsoCount QSOs without holding it in memory.
+//Only every fourth QSO has a time: the others are interpolated.
+type generatedLog struct {
+	qsoCount int
+	position int
+	pending  string
+}
+
+func (log *generatedLog) Read(data []byte) (int, error) {
+	for log.pending == "" {
+		switch {
+		case log.position == 0:
+			log.pending = "mycall on4kjm/p\ndate 2020-05-23 40m cw\n"
+		case log.position > log.qsoCount:
+			return 0, io.EOF
+		case (log.position-1)%4 == 0 || log.position == log.qsoCount:
+			minutes := (log.position - 1) / 4
+			if minutes > 0 && minutes%(24*60) == 0 {
+				log.pending = "day +\n"
+			}
+			log.pending += fmt.Sprintf("%02d%02d on4kjm 5\n", minutes/60%24, minutes%60)
+		default:
+			log.pending = "ik5zve\n"
+		}
+		log.position++
+	}
+	length := copy(data, log.pending)
+	log.pending = log.pending[length:]
+	return length, nil
+}
+
+func TestLogReader_largeLog(t *testing.T) {
+	const qsoCount = 20000
+	logReader := NewLogReader(&generatedLog{qsoCount: qsoCount}, true)
+
+	gotCount := 0
+	for {
+		if _, isRead := logReader.Next(); !isRead {
+			break
+		}
+		gotCount++
+		if len(logReader.gapQsos)+len(logReader.readyQsos) > 4 {
+			t.Fatalf("LogReader holds %d QSOs after %d QSOs", len(logReader.gapQsos)+len(logReader.readyQsos), gotCount)
+		}
+	}
+	if gotCount != qsoCount {
+		t.Errorf("LogReader.Next() returned %d QSOs, want %d", gotCount, qsoCount)
+	}
+	if HasErrors(logReader.Diagnostics()) {
+		t.Errorf("LogReader.Diagnostics() unexpected errors: %v", logReader.Diagnostics()[0])
+	}
+}
+
+func TestLogReader_limitDiagnostics(t *testing.T) {
+	input := "mycall on4kjm/p\n" +
+		"date 2020-05-23 40m cw\n" +
+		"1200 on4aaa\n" +
+		"1201 xxx\n" +
+		"1202 yyy\n" +
+		"1203 zzz\n"
+
+	logReader := NewLogReader(strings.NewReader(input), false)
+	logReader.limitDiagnostics(1)
+	for {
+		if _, isRead := logReader.Next(); !isRead {
+			break
+		}
+	}
+
+	diagnostics := logReader.Diagnostics()
+	if len(diagnostics) != 2 {
+		t.Fatalf("LogReader.Diagnostics() = %v, want 2 diagnostics", diagnostics)
+	}
+	wantSummary := Diagnostic{Code: CodeTooManyDiagnostics, Severity: SeverityError, Message: "2 more problems are not listed"}
+	if !reflect.DeepEqual(diagnostics[1], wantSummary) {
+		t.Errorf("LogReader.Diagnostics() summary = %v, want %v", diagnostics[1], wantSummary)
+	}
+	if len(logReader.diagnostics) != 1 {
+		t.Errorf("LogReader holds %d diagnostics, want 1", len(logReader.diagnostics))
+	}
+}
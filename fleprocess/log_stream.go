@@ -0,0 +1,111 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//LogWriter writes an output file one QSO at a time
+type LogWriter interface {
+	Write(logLine LogLine) error
+	Flush() error
+}
+
+//maxStreamedDiagnostics is the maximum number of problems listed when streaming a log
+const maxStreamedDiagnostics = 100
+
+//streamLogToFile loads the FLE input and writes the output file as the QSOs are read: the log is never held in memory as a whole.
+//Each QSO is displayed and checked before being written to a temporary file, which only becomes the output file
+//if the log could be loaded and checked without error.
+func streamLogToFile(inputFilename string, isInterpolateTime bool, outputFilename string, checker *logChecker, newLogWriter func(writer io.Writer) LogWriter, fileType string) error {
+	loadError := fmt.Errorf("There were input file parsing errors. Could not generate %s file", fileType)
+
+	file, err := os.Open(inputFilename)
+	if err != nil {
+		printLoadResult(0, fileAccessDiagnostics("failed opening file", err))
+		return loadError
+	}
+	defer file.Close()
+
+	temporaryFile, err := ioutil.TempFile(filepath.Dir(outputFilename), "."+filepath.Base(outputFilename)+".*")
+	if err != nil {
+		return fmt.Errorf("Could not create the %s file: %s", fileType, err)
+	}
+	isOutputWritten := false
+	defer func() {
+		if !isOutputWritten {
+			temporaryFile.Close()
+			os.Remove(temporaryFile.Name())
+		}
+	}()
+
+	lineCounter := &lineCountingWriter{writer: temporaryFile}
+	logWriter := newLogWriter(lineCounter)
+	logReader := NewLogReader(file, isInterpolateTime)
+	logReader.limitDiagnostics(maxStreamedDiagnostics)
+	printer := logPrinter{}
+	qsoCount := 0
+	for {
+		logLine, isRead := logReader.Next()
+		if !isRead {
+			break
+		}
+		qsoCount++
+		printer.print(logLine)
+		//A QSO with a problem is not written (the output file is discarded anyway)
+		if !checker.check(logLine) {
+			continue
+		}
+		if err := logWriter.Write(logLine); err != nil {
+			return fmt.Errorf("Could not write the %s file: %s", fileType, err)
+		}
+	}
+
+	diagnostics := logReader.Diagnostics()
+	if err := logReader.Err(); err != nil {
+		diagnostics = fileAccessDiagnostics("failed reading input", err)
+	}
+	if !printLoadResult(qsoCount, diagnostics) {
+		return loadError
+	}
+
+	//Check if we have all the necessary data
+	if err := checker.err(); err != nil {
+		return err
+	}
+
+	if err := logWriter.Flush(); err != nil {
+		return fmt.Errorf("Could not write the %s file: %s", fileType, err)
+	}
+	//The temporary file is only readable by its owner
+	temporaryFile.Chmod(0644)
+	if err := temporaryFile.Close(); err != nil {
+		return fmt.Errorf("Could not write the %s file: %s", fileType, err)
+	}
+	if err := os.Rename(temporaryFile.Name(), outputFilename); err != nil {
+		return fmt.Errorf("Could not create the %s file: %s", fileType, err)
+	}
+	isOutputWritten = true
+
+	fmt.Printf("\nSuccessfully wrote %d lines to file \"%s\"\n", lineCounter.lineCount, outputFilename)
+	return nil
+}
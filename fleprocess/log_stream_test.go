@@ -0,0 +1,102 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_streamLogToFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantErr       bool
+		wantLines     int
+	}{
+		{"Happy case", "../test/data/fle-1.txt", false, 11},
+		{"Parsing errors", "../test/data/fle-3-error.txt", true, 0},
+		{"No QSO", "../test/data/fle-4-no-qso.txt", true, 0},
+		{"Missing input file", "../test/data/no-such-file.txt", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDirectory, err := ioutil.TempDir("", "FLEcli-stream")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(outputDirectory)
+			outputFilename := filepath.Join(outputDirectory, "output.adi")
+
+			err = streamLogToFile(tt.inputFilename, false, outputFilename, newAdifChecker(AdifParams{}),
+				func(writer io.Writer) LogWriter { return NewAdifWriter(writer, AdifParams{}) }, "ADIF")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("streamLogToFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			files, _ := ioutil.ReadDir(outputDirectory)
+			if tt.wantErr {
+				//Nothing is left behind when the log can't be processed
+				if len(files) != 0 {
+					t.Errorf("streamLogToFile() left %d files", len(files))
+				}
+				return
+			}
+			if len(files) != 1 || files[0].Name() != "output.adi" {
+				t.Fatalf("streamLogToFile() generated %v", files)
+			}
+			output, _ := ioutil.ReadFile(outputFilename)
+			if lines := strings.Count(string(output), "\n"); lines != tt.wantLines {
+				t.Errorf("streamLogToFile() wrote %d lines, want %d", lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func Test_streamLogToFile_missingDate(t *testing.T) {
+	tests := []struct {
+		name         string
+		checker      *logChecker
+		newLogWriter func(writer io.Writer) LogWriter
+		fileType     string
+	}{
+		{"ADIF", newAdifChecker(AdifParams{}), func(writer io.Writer) LogWriter { return NewAdifWriter(writer, AdifParams{}) }, "ADIF"},
+		{"CSV", newSotaCsvChecker(), func(writer io.Writer) LogWriter { return NewCsvWriter(writer) }, "CSV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory, err := ioutil.TempDir("", "FLEcli-stream")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(directory)
+			inputFilename := filepath.Join(directory, "input.txt")
+			if err := ioutil.WriteFile(inputFilename, []byte("mycall on4kjm/p\nmysota on/on-001\n20m cw\n1310 s57lc\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err = streamLogToFile(inputFilename, false, filepath.Join(directory, "output"), tt.checker, tt.newLogWriter, tt.fileType)
+			if err == nil || !strings.Contains(err.Error(), "missing date for log entry at 1310 (#1)") {
+				t.Errorf("streamLogToFile() error = %v, want the missing date", err)
+			}
+		})
+	}
+}
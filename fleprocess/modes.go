@@ -0,0 +1,163 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//Documentation of the ADIF modes and submodes: https://adif.org/314/ADIF_314.htm#Mode_Enumeration
+
+import (
+	"regexp"
+	"strings"
+)
+
+//adifModes lists the ADIF modes with their submodes
+var adifModes = map[string][]string{
+	"AM":           nil,
+	"ARDOP":        nil,
+	"ATV":          nil,
+	"CHIP":         {"CHIP64", "CHIP128"},
+	"CLO":          nil,
+	"CONTESTI":     nil,
+	"CW":           {"PCW"},
+	"DIGITALVOICE": {"C4FM", "DMR", "DSTAR", "FREEDV", "M17"},
+	"DOMINO":       {"DOM-M", "DOM4", "DOM5", "DOM8", "DOM11", "DOM16", "DOM22", "DOM44", "DOM88", "DOMINOEX", "DOMINOF"},
+	"DYNAMIC":      {"VARA HF", "VARA SATELLITE", "VARA FM 1200", "VARA FM 9600"},
+	"FAX":          nil,
+	"FM":           nil,
+	"FSK441":       nil,
+	"FT8":          nil,
+	"HELL":         {"FMHELL", "FSKHELL", "HELL80", "HELLX5", "HELLX9", "HFSK", "PSKHELL", "SLOWHELL"},
+	"ISCAT":        {"ISCAT-A", "ISCAT-B"},
+	"JT4":          {"JT4A", "JT4B", "JT4C", "JT4D", "JT4E", "JT4F", "JT4G"},
+	"JT9": {"JT9-1", "JT9-2", "JT9-5", "JT9-10", "JT9-30", "JT9A", "JT9B", "JT9C", "JT9D", "JT9E", "JT9E FAST", "JT9F", "JT9F FAST",
+		"JT9G", "JT9G FAST", "JT9H", "JT9H FAST"},
+	"JT6M": nil,
+	"JT44": nil,
+	"JT65": {"JT65A", "JT65B", "JT65B2", "JT65C", "JT65C2"},
+	"MFSK": {"FSQCALL", "FST4", "FST4W", "FT4", "JS8", "JTMS", "MFSK4", "MFSK8", "MFSK11", "MFSK16", "MFSK22", "MFSK31", "MFSK32",
+		"MFSK64", "MFSK64L", "MFSK128", "MFSK128L", "Q65"},
+	"MSK144": nil,
+	"MT63":   nil,
+	"OLIVIA": {"OLIVIA 4/125", "OLIVIA 4/250", "OLIVIA 8/250", "OLIVIA 8/500", "OLIVIA 16/500", "OLIVIA 16/1000", "OLIVIA 32/1000"},
+	"OPERA":  {"OPERA-BEACON", "OPERA-QSO"},
+	"PAC":    {"PAC2", "PAC3", "PAC4"},
+	"PAX":    {"PAX2"},
+	"PKT":    nil,
+	"PSK": {"8PSK125", "8PSK125F", "8PSK125FL", "8PSK250", "8PSK250F", "8PSK250FL", "8PSK500", "8PSK500F", "8PSK1000", "8PSK1000F",
+		"8PSK1200F", "FSK31", "PSK10", "PSK31", "PSK63", "PSK63F", "PSK63RC4", "PSK63RC5", "PSK63RC10", "PSK63RC20", "PSK63RC32",
+		"PSK125", "PSK125C12", "PSK125R", "PSK125RC10", "PSK125RC12", "PSK125RC16", "PSK125RC4", "PSK125RC5", "PSK250", "PSK250C6",
+		"PSK250R", "PSK250RC2", "PSK250RC3", "PSK250RC5", "PSK250RC6", "PSK250RC7", "PSK500", "PSK500C2", "PSK500C4", "PSK500R",
+		"PSK500RC2", "PSK500RC3", "PSK500RC4", "PSK800C2", "PSK800RC2", "PSK1000", "PSK1000C2", "PSK1000R", "PSK1000RC2", "PSKAM10",
+		"PSKAM31", "PSKAM50", "PSKFEC31", "QPSK31", "QPSK63", "QPSK125", "QPSK250", "QPSK500", "SIM31"},
+	"PSK2K":  nil,
+	"Q15":    nil,
+	"QRA64":  {"QRA64A", "QRA64B", "QRA64C", "QRA64D", "QRA64E"},
+	"ROS":    {"ROS-EME", "ROS-HF", "ROS-MF"},
+	"RTTY":   {"ASCI"},
+	"RTTYM":  nil,
+	"SSB":    {"LSB", "USB"},
+	"SSTV":   nil,
+	"T10":    nil,
+	"THOR":   {"THOR-M", "THOR4", "THOR5", "THOR8", "THOR11", "THOR16", "THOR22", "THOR25X4", "THOR50X1", "THOR50X2", "THOR100"},
+	"THRB":   {"THRBX", "THRBX1", "THRBX2", "THRBX4", "THROB1", "THROB2", "THROB4"},
+	"TOR":    {"AMTORFEC", "GTOR", "NAVTEX", "SITORB"},
+	"V4":     nil,
+	"VOI":    nil,
+	"WINMOR": nil,
+	"WSPR":   nil,
+}
+
+//modeTypes gives the type of the modes with a report convention (CW: RST, PHONE: RS, DIGITAL: signed dB value).
+//Unlisted submodes have the type of their mode.
+var modeTypes = map[string]string{
+	"CW":     "CW",
+	"RTTY":   "CW",
+	"PSK":    "CW",
+	"SSB":    "PHONE",
+	"AM":     "PHONE",
+	"FM":     "PHONE",
+	"FSK441": "DIGITAL",
+	"FT8":    "DIGITAL",
+	"ISCAT":  "DIGITAL",
+	"JT4":    "DIGITAL",
+	"JT6M":   "DIGITAL",
+	"JT9":    "DIGITAL",
+	"JT44":   "DIGITAL",
+	"JT65":   "DIGITAL",
+	"MSK144": "DIGITAL",
+	"QRA64":  "DIGITAL",
+	"T10":    "DIGITAL",
+	"WSPR":   "DIGITAL",
+	"FST4":   "DIGITAL",
+	"FST4W":  "DIGITAL",
+	"FT4":    "DIGITAL",
+	"JS8":    "DIGITAL",
+	"Q65":    "DIGITAL",
+}
+
+//defaultReports gives the report used when none is entered, per mode type
+var defaultReports = map[string]string{
+	"CW":      "599",
+	"PHONE":   "59",
+	"DIGITAL": "-10",
+}
+
+//Mode is the definition of a FLE mode keyword
+type Mode struct {
+	AdifMode    string
+	AdifSubmode string
+	//ModeType is CW, PHONE or DIGITAL. It is empty if the mode has no report convention.
+	ModeType string
+}
+
+//fleModes maps the FLE mode keywords to their definition
+var fleModes = buildFleModes()
+
+//legacyModeKeywords are submodes that were FLE mode keywords before the submodes were supported
+var legacyModeKeywords = []string{"C4FM"}
+
+//buildFleModes creates the FLE mode keywords: the ADIF modes and submodes.
+//The submodes containing a blank can't be entered and the ones shaped like a call sign (JT4A) would hide it: they are left out.
+func buildFleModes() map[string]Mode {
+	isCallShaped := regexp.MustCompile("^" + validCallRegexp.String() + "$")
+
+	modes := make(map[string]Mode)
+	for adifMode, submodes := range adifModes {
+		modes[adifMode] = Mode{AdifMode: adifMode, ModeType: modeTypes[adifMode]}
+		for _, submode := range submodes {
+			if strings.Contains(submode, " ") || (isCallShaped.MatchString(submode) && !isInList(submode, legacyModeKeywords)) {
+				continue
+			}
+			modeType, isListed := modeTypes[submode]
+			if !isListed {
+				modeType = modeTypes[adifMode]
+			}
+			modes[submode] = Mode{AdifMode: adifMode, AdifSubmode: submode, ModeType: modeType}
+		}
+	}
+	return modes
+}
+
+//lookupMode returns true if the (uppercase) string is a FLE mode keyword
+func lookupMode(lookup string) bool {
+	_, isMode := fleModes[lookup]
+	return isMode
+}
+
+//isAdifSubmode returns true if the submode belongs to the ADIF mode (both in uppercase)
+func isAdifSubmode(adifMode, submode string) bool {
+	return isInList(submode, adifModes[adifMode])
+}
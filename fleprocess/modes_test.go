@@ -0,0 +1,89 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_fleModes(t *testing.T) {
+	tests := []struct {
+		keyword  string
+		wantMode Mode
+		wantOK   bool
+	}{
+		{"CW", Mode{AdifMode: "CW", ModeType: "CW"}, true},
+		{"PSK31", Mode{AdifMode: "PSK", AdifSubmode: "PSK31", ModeType: "CW"}, true},
+		{"USB", Mode{AdifMode: "SSB", AdifSubmode: "USB", ModeType: "PHONE"}, true},
+		{"FT4", Mode{AdifMode: "MFSK", AdifSubmode: "FT4", ModeType: "DIGITAL"}, true},
+		{"MFSK16", Mode{AdifMode: "MFSK", AdifSubmode: "MFSK16"}, true},
+		{"JT65B", Mode{AdifMode: "JT65", AdifSubmode: "JT65B", ModeType: "DIGITAL"}, false},
+		{"JT65B2", Mode{AdifMode: "JT65", AdifSubmode: "JT65B2", ModeType: "DIGITAL"}, true},
+		{"ISCAT-A", Mode{AdifMode: "ISCAT", AdifSubmode: "ISCAT-A", ModeType: "DIGITAL"}, true},
+		{"C4FM", Mode{AdifMode: "DIGITALVOICE", AdifSubmode: "C4FM"}, true},
+		{"VARA HF", Mode{}, false},
+		{"FOO", Mode{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keyword, func(t *testing.T) {
+			gotMode, gotOK := fleModes[tt.keyword]
+			if gotOK != tt.wantOK {
+				t.Fatalf("fleModes[%s] found = %v, want %v", tt.keyword, gotOK, tt.wantOK)
+			}
+			if gotOK && !reflect.DeepEqual(gotMode, tt.wantMode) {
+				t.Errorf("fleModes[%s] = %v, want %v", tt.keyword, gotMode, tt.wantMode)
+			}
+		})
+	}
+}
+
+//The mode keywords accepted before the mode table was introduced are still known
+func Test_lookupMode_previousKeywords(t *testing.T) {
+	previousKeywords := []string{"CW", "SSB", "AM", "FM", "RTTY", "FT8", "PSK", "JT65", "JT9", "FT4", "JS8", "ARDOP", "ATV", "C4FM", "CHIP",
+		"CLO", "CONTESTI", "DIGITALVOICE", "DOMINO", "DSTAR", "FAX", "FSK441", "HELL", "ISCAT", "JT4", "JT6M", "JT44", "MFSK", "MSK144",
+		"MT63", "OLIVIA", "OPERA", "PAC", "PAX", "PKT", "PSK2K", "Q15", "QRA64", "ROS", "RTTYM", "SSTV", "T10", "THOR", "THRB", "TOR",
+		"V4", "VOI", "WINMOR", "WSPR"}
+	for _, keyword := range previousKeywords {
+		if !lookupMode(keyword) {
+			t.Errorf("lookupMode(%s) = false, want true", keyword)
+		}
+	}
+}
+
+func Test_getDefaultReport(t *testing.T) {
+	tests := []struct {
+		mode              string
+		wantModeType      string
+		wantDefaultReport string
+	}{
+		{"CW", "CW", "599"},
+		{"PSK31", "CW", "599"},
+		{"LSB", "PHONE", "59"},
+		{"FT4", "DIGITAL", "-10"},
+		{"OLIVIA", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			gotModeType, gotDefaultReport := getDefaultReport(tt.mode)
+			if gotModeType != tt.wantModeType || gotDefaultReport != tt.wantDefaultReport {
+				t.Errorf("getDefaultReport() = %v, %v, want %v, %v", gotModeType, gotDefaultReport, tt.wantModeType, tt.wantDefaultReport)
+			}
+		})
+	}
+}
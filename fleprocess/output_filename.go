@@ -24,18 +24,9 @@ import (
 
 //buildOutputFilname will try to figure out an output filename (for the case none was provided)
 func buildOutputFilename(output string, input string, overwrite bool, newExtension string) (string, error) {
-
-	//validate that input is populated (should never happen if properly called)
-	if input == "" {
-		return "", fmt.Errorf("Unexepected error: no input file provided")
-	}
-
-	//No output was provided, let's create one from the input file
-	if output == "" {
-		extension := filepath.Ext(input)
-		outputRootPart := input[0 : len(input)-len(extension)]
-		output = outputRootPart + newExtension
-		fmt.Println("No output provided, defaulting to \"" + output + "\"")
+	output, err := defaultOutputFilename(output, input, newExtension)
+	if err != nil {
+		return "", err
 	}
 
 	//process the computed or user-provided output filename
@@ -55,3 +46,21 @@ func buildOutputFilename(output string, input string, overwrite bool, newExtensi
 
 	return "", fmt.Errorf("File already exists. Use --overwrite flag if necessary")
 }
+
+//defaultOutputFilename returns the output filename, derived from the input filename if none was provided.
+//The file itself is not checked: it can be the base of the names of the generated files.
+func defaultOutputFilename(output string, input string, newExtension string) (string, error) {
+	//validate that input is populated (should never happen if properly called)
+	if input == "" {
+		return "", fmt.Errorf("Unexepected error: no input file provided")
+	}
+
+	//No output was provided, let's create one from the input file
+	if output == "" {
+		extension := filepath.Ext(input)
+		outputRootPart := input[0 : len(input)-len(extension)]
+		output = outputRootPart + newExtension
+		fmt.Println("No output provided, defaulting to \"" + output + "\"")
+	}
+	return output, nil
+}
@@ -19,6 +19,7 @@ limitations under the License.
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	//"fmt"
@@ -26,56 +27,53 @@ import (
 
 // LogLine is used to store all the data of a single log line
 type LogLine struct {
-	Date             string
-	MyCall           string
-	Operator         string
-	MyWWFF           string
-	MySOTA           string
-	MyGrid           string
-	QslMsgFromHeader string
-	Nickname         string
-	Mode             string
-	ModeType         string
-	Band             string
-	BandLowerLimit   float64
-	BandUpperLimit   float64
-	Frequency        string
-	Time             string
-	ActualTime       string //time actually recorded in FLE
-	Call             string
-	Comment          string
-	QSLmsg           string
-	OMname           string
-	GridLoc          string
-	RSTsent          string
-	RSTrcvd          string
-	WWFF             string
-	SOTA             string
+	Date             string   `json:"date,omitempty"`
+	MyCall           string   `json:"myCall,omitempty"`
+	Operator         string   `json:"operator,omitempty"`
+	MyWWFF           string   `json:"myWwff,omitempty"`
+	MySOTA           string   `json:"mySota,omitempty"`
+	MyPOTA           string   `json:"myPota,omitempty"`
+	MyGrid           string   `json:"myGrid,omitempty"`
+	QslMsgFromHeader string   `json:"qslMsgFromHeader,omitempty"`
+	Nickname         string   `json:"nickname,omitempty"`
+	Region           string   `json:"region,omitempty"` //IARU region selecting the band plan
+	Mode             string   `json:"mode,omitempty"`
+	ModeType         string   `json:"modeType,omitempty"`
+	Band             string   `json:"band,omitempty"`
+	BandLowerLimit   float64  `json:"bandLowerLimit,omitempty"`
+	BandUpperLimit   float64  `json:"bandUpperLimit,omitempty"`
+	Frequency        string   `json:"frequency,omitempty"`
+	Time             string   `json:"time,omitempty"`
+	ActualTime       string   `json:"actualTime,omitempty"` //time actually recorded in FLE
+	Call             string   `json:"call,omitempty"`
+	Comment          string   `json:"comment,omitempty"`
+	QSLmsg           string   `json:"qslMsg,omitempty"`
+	OMname           string   `json:"omName,omitempty"`
+	GridLoc          string   `json:"gridLoc,omitempty"`
+	RSTsent          string   `json:"rstSent,omitempty"`
+	RSTrcvd          string   `json:"rstRcvd,omitempty"`
+	WWFF             string   `json:"wwff,omitempty"`
+	SOTA             string   `json:"sota,omitempty"`
+	POTA             string   `json:"pota,omitempty"`
+	STX              string   `json:"stx,omitempty"`           //sent contest serial number
+	SRX              string   `json:"srx,omitempty"`           //received contest serial number
+	STXstring        string   `json:"stxString,omitempty"`     //sent contest exchange
+	SRXstring        string   `json:"srxString,omitempty"`     //received contest exchange
+	InvalidFields    FieldSet `json:"invalidFields,omitempty"` //fields holding a value that failed validation
 }
 
-var regexpIsFullTime = regexp.MustCompile("^[0-2]{1}[0-9]{3}$")
-var regexpIsTimePart = regexp.MustCompile("^[0-5]{1}[0-9]{1}$|^[1-9]{1}$")
-var regexpIsOMname = regexp.MustCompile("^@")
-var regexpIsGridLoc = regexp.MustCompile("^#")
-var regexpIsRst = regexp.MustCompile("^[\\d]{1,3}$")
-var regexpIsFreq = regexp.MustCompile("^[\\d]+\\.[\\d]+$")
-var regexpIsSotaKeyWord = regexp.MustCompile("(?i)^sota$")
-var regexpIsWwffKeyWord = regexp.MustCompile("(?i)^wwff$")
-var regexpDatePattern = regexp.MustCompile("^(\\d{2}|\\d{4})[-/ .]\\d{1,2}[-/ .]\\d{1,2}$")
-var regexpIsDateKeyWord = regexp.MustCompile("(?i)^date$")
-var regexpDayIncrementPattern = regexp.MustCompile("^\\+*$")
-var regexpIsDayKeyword = regexp.MustCompile("(?i)^day$")
+var regexpIsSerial = regexp.MustCompile("^[\\d]+$")
 
 // ParseLine cuts a FLE line into useful bits
-func ParseLine(inputStr string, previousLine LogLine) (logLine LogLine, errorMsg string) {
-	//TODO: input null protection?
-
-	//Flag telling that we are processing data to the right of the callsign
-	isRightOfCall := false
-
-	//Flag used to know if we are parsing the Sent RST (first) or received RST (second)
-	haveSentRST := false
+//The problems found are returned as diagnostics pointing at the offending element of the line (the line number is not set).
+func ParseLine(inputStr string, previousLine LogLine) (logLine LogLine, diagnostics []Diagnostic) {
+	dataLine, parseDiagnostics := parseDataElements(inputStr, 0)
+	return evaluateDataLine(dataLine, parseDiagnostics, previousLine)
+}
 
+//evaluateDataLine applies the elements of a parsed data line to the values of the previous line.
+//The diagnostics of the parser and of the evaluation are returned in the order of the line.
+func evaluateDataLine(dataLine DataLine, parseDiagnostics []Diagnostic, previousLine LogLine) (logLine LogLine, diagnostics []Diagnostic) {
 	//TODO: Make something more intelligent
 	//TODO: What happens if we have partial lines
 	previousLine.Call = ""
@@ -83,31 +81,35 @@ func ParseLine(inputStr string, previousLine LogLine) (logLine LogLine, errorMsg
 	previousLine.RSTrcvd = ""
 	previousLine.SOTA = ""
 	previousLine.WWFF = ""
+	previousLine.POTA = ""
 	previousLine.OMname = ""
 	previousLine.GridLoc = ""
 	previousLine.Comment = ""
 	previousLine.ActualTime = ""
+	previousLine.STX = ""
+	previousLine.SRX = ""
+	previousLine.SRXstring = ""
+	//The sent exchange string (STXstring) is kept as it is usually the same for the whole contest
+	for _, field := range qsoFields {
+		previousLine.InvalidFields = previousLine.InvalidFields.with(field, false)
+	}
 	logLine = previousLine
 
-	//TODO: what happens when we have <> or when there are multiple comments
-	//TODO: Refactor this! it is ugly
-	comment, inputStr := getBraketedData(inputStr, COMMENT)
-	if comment != "" {
-		logLine.Comment = comment
-	}
+	diagnostics = append(diagnostics, parseDiagnostics...)
 
-	QSLmsg, inputStr := getBraketedData(inputStr, QSL)
-	if QSLmsg != "" {
-		logLine.QSLmsg = QSLmsg
+	//addDiagnostic records a problem located at the supplied element of the line
+	addDiagnostic := func(diagnostic *Diagnostic, element Token) {
+		located := diagnostic.at(element.Column, element.Length)
+		located.Line = dataLine.Line
+		diagnostics = append(diagnostics, located)
 	}
 
-	elements := strings.Fields(inputStr)
-
-	for _, element := range elements {
+	for _, element := range dataLine.Elements {
+		text := element.Text
 
-		//  Is it a mode?
-		if lookupMode(strings.ToUpper(element)) {
-			logLine.Mode = strings.ToUpper(element)
+		switch element.Type {
+		case TokenMode:
+			logLine.Mode = strings.ToUpper(text)
 			//TODO: improve this: what if the band is at the end of the line
 			// Set the default RST depending of the mode
 			if (logLine.RSTsent == "") || (logLine.RSTrcvd == "") {
@@ -118,194 +120,153 @@ func ParseLine(inputStr string, previousLine LogLine) (logLine LogLine, errorMsg
 				logLine.RSTrcvd = defaultReport
 
 			} else {
-				errorMsg = errorMsg + "Double definitiion of RST"
+				addDiagnostic(newDiagnostic(CodeInvalidReport, "Double definition of RST"), element)
 			}
-			continue
-		}
 
-		//Date?
-		if regexpDatePattern.MatchString(element) {
+		case TokenDate:
 			//We probably have a date, let's normalize it
-			errorTxt := ""
-			normalizedDate := ""
-			normalizedDate, errorTxt = NormalizeDate(element)
+			var dateDiagnostic *Diagnostic
+			normalizedDate, errorTxt := NormalizeDate(text)
 			if len(errorTxt) != 0 {
 				logLine.Date = normalizedDate
-				errorMsg = errorMsg + fmt.Sprintf("Invalid Date: %s (%s)", element, errorTxt)
+				dateDiagnostic = newDiagnostic(CodeInvalidDate, "Invalid Date: %s (%s)", text, errorTxt)
 			} else {
-				logLine.Date, errorTxt = ValidateDate(normalizedDate)
-				if len(errorTxt) != 0 {
-					errorMsg = errorMsg + fmt.Sprintf("Error %s", errorTxt)
-				}
+				logLine.Date, dateDiagnostic = ValidateDate(normalizedDate)
+			}
+			logLine.setValidity(FieldDate, dateDiagnostic)
+			if dateDiagnostic != nil {
+				addDiagnostic(dateDiagnostic, element)
 			}
-			continue
-		}
-
-		// The date keyword is not really useful, skip it
-		if regexpIsDateKeyWord.MatchString(element) {
-			continue
-		}
-
-		//Skip the "day" keyword
-		if regexpIsDayKeyword.MatchString(element) {
-			continue
-		}
 
-		//Scan the + part
-		if regexpDayIncrementPattern.MatchString(element) {
-			increment := len(element)
-			newDate, dateError := IncrementDate(logLine.Date, increment)
+		case TokenDayIncrement:
+			var dateDiagnostic *Diagnostic
+			newDate, dateError := IncrementDate(logLine.Date, len(text))
 			if dateError != "" {
-				errorMsg = errorMsg + fmt.Sprintf(dateError)
+				dateDiagnostic = newDiagnostic(CodeInvalidDate, "%s", dateError)
+				addDiagnostic(dateDiagnostic, element)
 			}
 			logLine.Date = newDate
-			continue
-		}
-
-		// Is it a band?
-		isBandElement, bandLowerLimit, bandUpperLimit, _ := IsBand(element)
-		if isBandElement {
-			logLine.Band = strings.ToLower(element)
-			logLine.BandLowerLimit = bandLowerLimit
-			logLine.BandUpperLimit = bandUpperLimit
-			continue
-		}
+			logLine.setValidity(FieldDate, dateDiagnostic)
+
+		case TokenBand:
+			band, isAllocated := lookupBand(text, logLine.Region)
+			logLine.Band = strings.ToLower(text)
+			logLine.BandLowerLimit = band.LowerLimit
+			logLine.BandUpperLimit = band.UpperLimit
+			if !isAllocated {
+				addDiagnostic(newDiagnostic(CodeInvalidBand, "The %s band is not allocated in IARU region %s.", logLine.Band, effectiveRegion(logLine.Region)), element)
+			}
 
-		// Is it a Frequency?
-		if regexpIsFreq.MatchString(element) {
-			var qrg float64
-			qrg, _ = strconv.ParseFloat(element, 32)
-			if (logLine.BandLowerLimit != 0.0) && (logLine.BandUpperLimit != 0.0) {
+		case TokenFrequency:
+			qrg, isKhzInteger := frequencyInMhz(text)
+			//A band that is set must match the frequency
+			if logLine.BandUpperLimit != 0.0 {
 				if (qrg >= logLine.BandLowerLimit) && (qrg <= logLine.BandUpperLimit) {
 					logLine.Frequency = fmt.Sprintf("%.3f", qrg)
+				} else if isKhzInteger {
+					//An integer is only a frequency in kHz if it is within the current band (1840 is a time, not 1.840 MHz)
+					addDiagnostic(newDiagnostic(CodeInvalidFrequency, "Unable to make sense of [%s]: it is not a frequency (in kHz) of the current band.", text), element)
 				} else {
 					logLine.Frequency = ""
-					errorMsg = errorMsg + "Frequency [" + element + "] is invalid for " + logLine.Band + " band."
+					addDiagnostic(newDiagnostic(CodeInvalidFrequency, "Frequency [%s] is invalid for %s band.", text, logLine.Band), element)
 				}
+				break
+			}
+			//Without band, the band is derived from the frequency
+			logLine.Frequency = ""
+			if band, isFound := lookupBandOfFrequency(qrg, logLine.Region); isFound {
+				logLine.Band = band.Name
+				logLine.BandLowerLimit = band.LowerLimit
+				logLine.BandUpperLimit = band.UpperLimit
+				logLine.Frequency = fmt.Sprintf("%.3f", qrg)
+			} else if isKhzInteger {
+				addDiagnostic(newDiagnostic(CodeInvalidFrequency, "Unable to make sense of [%s]: it is not a frequency (in kHz) of an amateur band.", text), element)
 			} else {
-				errorMsg = errorMsg + "Unable to load frequency [" + element + "]: no band defined for that frequency."
+				frequencyDiagnostic := newDiagnostic(CodeInvalidFrequency, "Frequency [%s] is outside of the amateur bands: it is ignored.", text)
+				frequencyDiagnostic.Severity = SeverityWarning
+				addDiagnostic(frequencyDiagnostic, element)
 			}
-			continue
-		}
 
-		// Is it a call sign ?
-		//FIXME:
-		if validCallRegexp.MatchString(strings.ToUpper(element)) {
-			//If it starts with "#",it is a grid definition and not a call
-			if element[0] != '#' {
-				callErrorMsg := ""
-				logLine.Call, callErrorMsg = ValidateCall(element)
-				errorMsg = errorMsg + callErrorMsg
-				isRightOfCall = true
-				continue
+		case TokenSentExchange:
+			serial, exchange := splitContestExchange(strings.TrimLeft(text, ","))
+			if serial != "" {
+				logLine.STX = serial
+			} else {
+				logLine.STXstring = exchange
 			}
-		}
 
-		// Is it a "full" time ?
-		if isRightOfCall == false {
-			if regexpIsFullTime.MatchString(element) {
-				logLine.Time = element
-				logLine.ActualTime = element
-				continue
+		case TokenRcvdExchange:
+			serial, exchange := splitContestExchange(strings.TrimLeft(text, "."))
+			if serial != "" {
+				logLine.SRX = serial
+			} else {
+				logLine.SRXstring = exchange
 			}
 
-			// Is it a partial time ?
-			if regexpIsTimePart.MatchString(element) {
-				if logLine.Time == "" {
-					logLine.Time = element
-					logLine.ActualTime = element
-				} else {
-					goodPart := logLine.Time[:len(logLine.Time)-len(element)]
-					logLine.Time = goodPart + element
-					logLine.ActualTime = goodPart + element
-				}
-				continue
+		case TokenCall:
+			var callDiagnostic *Diagnostic
+			if logLine.Call, callDiagnostic = ValidateCall(text); callDiagnostic != nil {
+				addDiagnostic(callDiagnostic, element)
 			}
-		}
+			logLine.setValidity(FieldCall, callDiagnostic)
 
-		// Is it the OM's name (starting with "@")
-		if regexpIsOMname.MatchString(element) {
-			logLine.OMname = strings.TrimLeft(element, "@")
-			continue
-		}
+		case TokenTime:
+			if regexpIsFullTime.MatchString(text) || logLine.Time == "" {
+				logLine.Time = text
+				logLine.ActualTime = text
+			} else {
+				// partial time: only the last digits are replaced
+				goodPart := logLine.Time[:len(logLine.Time)-len(text)]
+				logLine.Time = goodPart + text
+				logLine.ActualTime = goodPart + text
+			}
 
-		// Is it the Grid Locator (starting with "#")
-		if regexpIsGridLoc.MatchString(element) {
-			grid := strings.TrimLeft(element, "#")
-			cleanGrid, callErrorMsg := ValidateGridLocator(grid)
-			logLine.GridLoc = cleanGrid
-			errorMsg = errorMsg + callErrorMsg
-			continue
-		}
+		case TokenName:
+			logLine.OMname = strings.TrimLeft(text, "@")
 
-		if isRightOfCall {
-			//This is probably a RST
-			if regexpIsRst.MatchString(element) {
-				workRST := ""
-				switch len(element) {
-				case 1:
-					if logLine.ModeType == "CW" {
-						workRST = "5" + element + "9"
-					} else {
-						if logLine.ModeType == "PHONE" {
-							workRST = "5" + element
-						}
-					}
-				case 2:
-					if logLine.ModeType == "CW" {
-						workRST = element + "9"
-					} else {
-						if logLine.ModeType == "PHONE" {
-							workRST = element
-						}
-					}
-				case 3:
-					if logLine.ModeType == "CW" {
-						workRST = element
-					} else {
-						workRST = "*" + element
-						errorMsg = errorMsg + "Invalid report [" + element + "] for " + logLine.ModeType + " mode."
-					}
-				}
-				if haveSentRST {
-					logLine.RSTrcvd = workRST
-				} else {
-					logLine.RSTsent = workRST
-					haveSentRST = true
-				}
-				continue
+		case TokenGrid:
+			grid := strings.TrimLeft(text, "#")
+			cleanGrid, gridDiagnostic := ValidateGridLocator(grid)
+			logLine.GridLoc = cleanGrid
+			logLine.setValidity(FieldGridLoc, gridDiagnostic)
+			if gridDiagnostic != nil {
+				addDiagnostic(gridDiagnostic, element)
 			}
 
-			// If the "wwff" keyword is used, skip it
-			if regexpIsWwffKeyWord.MatchString(element) {
-				// this keyword is not requiered anymore with FLE 3 and doesn't add any value
-				continue
+		case TokenSentReport, TokenRcvdReport:
+			workRST, rstDiagnostic := evaluateReport(text, logLine.ModeType)
+			if rstDiagnostic != nil {
+				addDiagnostic(rstDiagnostic, element)
 			}
-
-			// Is it a "WWFF to WWFF" reference?
-			workRef, wwffErr := ValidateWwff(element)
-			if wwffErr == "" {
-				logLine.WWFF = workRef
-				continue
+			if element.Type == TokenRcvdReport {
+				logLine.RSTrcvd = workRST
+				logLine.setValidity(FieldRSTrcvd, rstDiagnostic)
+			} else {
+				logLine.RSTsent = workRST
+				logLine.setValidity(FieldRSTsent, rstDiagnostic)
 			}
 
-			// If the "sota" keyword is used, skip it
-			if regexpIsSotaKeyWord.MatchString(element) {
-				// this keyword is not requiered anymore with FLE 3 and doesn't add any value
-				continue
+		case TokenWwffRef:
+			logLine.WWFF, _ = ValidateWwff(text)
+
+		case TokenSotaRef:
+			logLine.SOTA, _ = ValidateSota(text)
+
+		case TokenPotaRef:
+			logLine.POTA, _ = ValidatePota(text)
+
+		case TokenComment:
+			if text != "" {
+				logLine.Comment = text
 			}
 
-			// Is it a Summit to Summit (sota) reference?
-			workRef, sotaErr := ValidateSota(element)
-			if sotaErr == "" {
-				logLine.SOTA = workRef
-				continue
+		case TokenQslMessage:
+			if text != "" {
+				logLine.QSLmsg = text
 			}
 		}
-
-		//If we come here, we could not make sense of what we found
-		errorMsg = errorMsg + "Unable to make sense of [" + element + "]. "
-
+		//The keywords (date, day, wwff, sota, pota) don't add any value and the unknown elements
+		//have already been reported by the parser
 	}
 
 	//If no report is present, let's fill it with mode default
@@ -319,62 +280,63 @@ func ParseLine(inputStr string, previousLine LogLine) (logLine LogLine, errorMsg
 	//For debug purposes
 	//fmt.Println("\n", SprintLogRecord(logLine))
 
-	return logLine, errorMsg
+	sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].Column < diagnostics[j].Column })
+	return logLine, diagnostics
+}
+
+// splitContestExchange tells whether the contest exchange is a serial number or a free exchange string.
+// Serial numbers are returned without their leading zeros, other exchanges are returned in uppercase.
+func splitContestExchange(exchange string) (serial, exchangeString string) {
+	if regexpIsSerial.MatchString(exchange) {
+		number, _ := strconv.Atoi(exchange)
+		return strconv.Itoa(number), ""
+	}
+	return "", strings.ToUpper(exchange)
 }
 
-func lookupMode(lookup string) bool {
-	switch lookup {
-	case
-		"CW",
-		"SSB",
-		"AM",
-		"FM",
-		"RTTY",
-		"FT8",
-		"PSK",
-		"JT65",
-		"JT9",
-		"FT4",
-		"JS8",
-		"ARDOP",
-		"ATV",
-		"C4FM",
-		"CHIP",
-		"CLO",
-		"CONTESTI",
-		"DIGITALVOICE",
-		"DOMINO",
-		"DSTAR",
-		"FAX",
-		"FSK441",
-		"HELL",
-		"ISCAT",
-		"JT4",
-		"JT6M",
-		"JT44",
-		"MFSK",
-		"MSK144",
-		"MT63",
-		"OLIVIA",
-		"OPERA",
-		"PAC",
-		"PAX",
-		"PKT",
-		"PSK2K",
-		"Q15",
-		"QRA64",
-		"ROS",
-		"RTTYM",
-		"SSTV",
-		"T10",
-		"THOR",
-		"THRB",
-		"TOR",
-		"V4",
-		"VOI",
-		"WINMOR",
-		"WSPR":
-		return true
+//Range of the signal reports of the digital modes (in dB)
+const (
+	minDbReport = -50
+	maxDbReport = 50
+)
+
+//evaluateReport returns the full report for the mode type. In CW and phone, the missing digits of the RST are added
+//(e.g. "5" becomes "559" in CW). The digital modes use a signed dB value, formatted with two digits (e.g. "-05").
+func evaluateReport(report, modeType string) (rst string, diagnostic *Diagnostic) {
+	if modeType == "DIGITAL" {
+		if !regexpIsDbReport.MatchString(report) {
+			return report, newDiagnostic(CodeInvalidReport, "Invalid report [%s] for DIGITAL mode: a signed dB value (like -12 or +05) is expected.", report)
+		}
+		dbValue, _ := strconv.Atoi(report)
+		if dbValue < minDbReport || dbValue > maxDbReport {
+			return report, newDiagnostic(CodeInvalidReport, "Invalid report [%s]: the dB value must be between %d and +%d.", report, minDbReport, maxDbReport)
+		}
+		return fmt.Sprintf("%+03d", dbValue), nil
+	}
+	if regexpIsDbReport.MatchString(report) {
+		return report, newDiagnostic(CodeInvalidReport, "Invalid report [%s] for %s mode.", report, modeType)
+	}
+
+	switch len(report) {
+	case 1:
+		if modeType == "CW" {
+			return "5" + report + "9", nil
+		}
+		if modeType == "PHONE" {
+			return "5" + report, nil
+		}
+	case 2:
+		if modeType == "CW" {
+			return report + "9", nil
+		}
+		if modeType == "PHONE" {
+			return report, nil
+		}
+	case 3:
+		if modeType == "CW" {
+			return report, nil
+		}
+		return report, newDiagnostic(CodeInvalidReport, "Invalid report [%s] for %s mode.", report, modeType)
 	}
-	return false
+	return "", nil
 }
@@ -76,7 +76,7 @@ func TestParseLine(t *testing.T) {
 		{
 			"Wrong mode",
 			args{inputStr: "cww", previousLine: LogLine{Mode: "SSB"}},
-			LogLine{Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "Unable to make sense of [cww]. ",
+			LogLine{Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "Unable to make sense of [cww].",
 		},
 		{
 			"Parse OM name",
@@ -91,7 +91,12 @@ func TestParseLine(t *testing.T) {
 		{
 			"Parse Grid locator NOK",
 			args{inputStr: "#grid", previousLine: LogLine{Mode: "SSB"}},
-			LogLine{GridLoc: "*grid", Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "[grid] is an invalid grid reference",
+			LogLine{GridLoc: "grid", Mode: "SSB", RSTsent: "59", RSTrcvd: "59", InvalidFields: FieldSet(0).with(FieldGridLoc, true)}, "[grid] is an invalid grid reference",
+		},
+		{
+			"Invalid QSO values are not carried over",
+			args{inputStr: "1315 on4do", previousLine: LogLine{Date: "2020-13-01", Call: "G3NOH", GridLoc: "grid", Mode: "SSB", InvalidFields: FieldSet(0).with(FieldDate, true).with(FieldGridLoc, true)}},
+			LogLine{Date: "2020-13-01", Time: "1315", ActualTime: "1315", Call: "ON4DO", Mode: "SSB", RSTsent: "59", RSTrcvd: "59", InvalidFields: FieldSet(0).with(FieldDate, true)}, "",
 		},
 		{
 			"Parse frequency",
@@ -106,7 +111,82 @@ func TestParseLine(t *testing.T) {
 		{
 			"Parse frequency out of limit with no band defined",
 			args{inputStr: "14.453 on4kjm", previousLine: LogLine{Mode: "SSB"}},
-			LogLine{Call: "ON4KJM", Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "Unable to load frequency [14.453]: no band defined for that frequency.",
+			LogLine{Call: "ON4KJM", Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "Frequency [14.453] is outside of the amateur bands: it is ignored.",
+		},
+		{
+			"Parse frequency out of the band of the line",
+			args{inputStr: "20m 7.018 on4kjm", previousLine: LogLine{Mode: "CW"}},
+			LogLine{Band: "20m", BandLowerLimit: 14.0, BandUpperLimit: 14.35, Call: "ON4KJM", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "Frequency [7.018] is invalid for 20m band.",
+		},
+		{
+			"Band derived from the frequency",
+			args{inputStr: "7.018 on4kjm", previousLine: LogLine{Mode: "CW"}},
+			LogLine{Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3, Frequency: "7.018", Call: "ON4KJM", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "",
+		},
+		{
+			"Band not changed by the frequency",
+			args{inputStr: "14.062 on4kjm", previousLine: LogLine{Mode: "CW", Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3, Frequency: "7.018"}},
+			LogLine{Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3, Call: "ON4KJM", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "Frequency [14.062] is invalid for 40m band.",
+		},
+		{
+			"Band of the line and frequency",
+			args{inputStr: "20m 14.062 on4kjm", previousLine: LogLine{Mode: "CW", Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3, Frequency: "7.018"}},
+			LogLine{Band: "20m", BandLowerLimit: 14.0, BandUpperLimit: 14.35, Frequency: "14.062", Call: "ON4KJM", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "",
+		},
+		{
+			"Parse frequency in kHz",
+			args{inputStr: "7018 on4kjm", previousLine: LogLine{Mode: "CW", Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3}},
+			LogLine{Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3, Frequency: "7.018", Call: "ON4KJM", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "",
+		},
+		{
+			"Parse frequency in kHz out of the current band",
+			args{inputStr: "14062 on4kjm", previousLine: LogLine{Mode: "CW", Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3}},
+			LogLine{Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3, Call: "ON4KJM", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "Unable to make sense of [14062]: it is not a frequency (in kHz) of the current band.",
+		},
+		{
+			"Parse frequency in kHz without band",
+			args{inputStr: "7018 04 on5abc", previousLine: LogLine{Mode: "CW", Time: "1200"}},
+			LogLine{Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3, Frequency: "7.018", Time: "1204", ActualTime: "1204", Call: "ON5ABC", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "",
+		},
+		{
+			"Parse frequency in kHz without band out of the amateur bands",
+			args{inputStr: "9000 on5abc", previousLine: LogLine{Mode: "CW"}},
+			LogLine{Call: "ON5ABC", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "Unable to make sense of [9000]: it is not a frequency (in kHz) of an amateur band.",
+		},
+		{
+			"Parse frequency with kHz unit",
+			args{inputStr: "14062k on4kjm", previousLine: LogLine{Mode: "CW"}},
+			LogLine{Band: "20m", BandLowerLimit: 14.0, BandUpperLimit: 14.35, Frequency: "14.062", Call: "ON4KJM", Mode: "CW", RSTsent: "599", RSTrcvd: "599"}, "",
+		},
+		{
+			"Parse frequency with MHz unit",
+			args{inputStr: "144.300M on4kjm", previousLine: LogLine{Mode: "SSB"}},
+			LogLine{Band: "2m", BandLowerLimit: 144, BandUpperLimit: 148, Frequency: "144.300", Call: "ON4KJM", Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "",
+		},
+		{
+			"Parse frequency with GHz unit",
+			args{inputStr: "3cm 10.368G on4kjm", previousLine: LogLine{Mode: "SSB"}},
+			LogLine{Band: "3cm", BandLowerLimit: 10000, BandUpperLimit: 10500, Frequency: "10368.000", Call: "ON4KJM", Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "",
+		},
+		{
+			"Band derived from the frequency in the region",
+			args{inputStr: "7.250 on4kjm", previousLine: LogLine{Mode: "SSB", Region: "1"}},
+			LogLine{Region: "1", Call: "ON4KJM", Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "Frequency [7.250] is outside of the amateur bands: it is ignored.",
+		},
+		{
+			"Parse band of the region",
+			args{inputStr: "40m 7.150 on4kjm", previousLine: LogLine{Mode: "SSB", Region: "1"}},
+			LogLine{Region: "1", Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.2, Frequency: "7.150", Call: "ON4KJM", Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "",
+		},
+		{
+			"Parse frequency out of the region limits",
+			args{inputStr: "40m 7.250 on4kjm", previousLine: LogLine{Mode: "SSB", Region: "1"}},
+			LogLine{Region: "1", Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.2, Call: "ON4KJM", Mode: "SSB", RSTsent: "59", RSTrcvd: "59"}, "Frequency [7.250] is invalid for 40m band.",
+		},
+		{
+			"Parse band not allocated in the region",
+			args{inputStr: "1.25m on4kjm", previousLine: LogLine{Mode: "FM", Region: "1"}},
+			LogLine{Region: "1", Band: "1.25m", Call: "ON4KJM", Mode: "FM", RSTsent: "59", RSTrcvd: "59"}, "The 1.25m band is not allocated in IARU region 1.",
 		},
 		{
 			"parse partial RST (sent) - CW",
@@ -136,7 +216,35 @@ func TestParseLine(t *testing.T) {
 		{
 			"Incompatible report",
 			args{inputStr: "1230 on4kjm 5 599", previousLine: LogLine{Mode: "FM", ModeType: "PHONE"}},
-			LogLine{Call: "ON4KJM", Time: "1230", ActualTime: "1230", RSTsent: "55", RSTrcvd: "*599", Mode: "FM", ModeType: "PHONE"}, "Invalid report [599] for PHONE mode.",
+			LogLine{Call: "ON4KJM", Time: "1230", ActualTime: "1230", RSTsent: "55", RSTrcvd: "599", Mode: "FM", ModeType: "PHONE", InvalidFields: FieldSet(0).with(FieldRSTrcvd, true)}, "Invalid report [599] for PHONE mode.",
+		},
+		{
+			"dB reports - FT8",
+			args{inputStr: "1230 dl1aa -15 +3", previousLine: LogLine{Mode: "FT8", ModeType: "DIGITAL"}},
+			LogLine{Call: "DL1AA", Time: "1230", ActualTime: "1230", RSTsent: "-15", RSTrcvd: "+03", Mode: "FT8", ModeType: "DIGITAL"}, "",
+		},
+		{
+			"dB report (sent only) - FT4",
+			args{inputStr: "1230 dl1aa -5", previousLine: LogLine{Mode: "FT4", ModeType: "DIGITAL"}},
+			LogLine{Call: "DL1AA", Time: "1230", ActualTime: "1230", RSTsent: "-05", RSTrcvd: "-10", Mode: "FT4", ModeType: "DIGITAL"}, "",
+		},
+		{
+			"dB report out of range",
+			args{inputStr: "1230 dl1aa -65 -12", previousLine: LogLine{Mode: "FT8", ModeType: "DIGITAL"}},
+			LogLine{Call: "DL1AA", Time: "1230", ActualTime: "1230", RSTsent: "-65", RSTrcvd: "-12", Mode: "FT8", ModeType: "DIGITAL", InvalidFields: FieldSet(0).with(FieldRSTsent, true)},
+			"Invalid report [-65]: the dB value must be between -50 and +50.",
+		},
+		{
+			"Unsigned report - FT8",
+			args{inputStr: "1230 dl1aa 15", previousLine: LogLine{Mode: "FT8", ModeType: "DIGITAL"}},
+			LogLine{Call: "DL1AA", Time: "1230", ActualTime: "1230", RSTsent: "15", RSTrcvd: "-10", Mode: "FT8", ModeType: "DIGITAL", InvalidFields: FieldSet(0).with(FieldRSTsent, true)},
+			"Invalid report [15] for DIGITAL mode: a signed dB value (like -12 or +05) is expected.",
+		},
+		{
+			"dB report - CW",
+			args{inputStr: "1230 dl1aa -15", previousLine: LogLine{Mode: "CW", ModeType: "CW"}},
+			LogLine{Call: "DL1AA", Time: "1230", ActualTime: "1230", RSTsent: "-15", RSTrcvd: "599", Mode: "CW", ModeType: "CW", InvalidFields: FieldSet(0).with(FieldRSTsent, true)},
+			"Invalid report [-15] for CW mode.",
 		},
 		{
 			"SOTA keywork ",
@@ -158,6 +266,16 @@ func TestParseLine(t *testing.T) {
 			args{inputStr: "1230 oe6cud/p onff-0258", previousLine: LogLine{Mode: "FM", ModeType: "PHONE"}},
 			LogLine{Call: "OE6CUD/P", Time: "1230", ActualTime: "1230", RSTsent: "59", RSTrcvd: "59", Mode: "FM", ModeType: "PHONE", WWFF: "ONFF-0258"}, "",
 		},
+		{
+			"POTA keywork ",
+			args{inputStr: "1230 k1abc pota k-1234", previousLine: LogLine{Mode: "FM", ModeType: "PHONE"}},
+			LogLine{Call: "K1ABC", Time: "1230", ActualTime: "1230", RSTsent: "59", RSTrcvd: "59", Mode: "FM", ModeType: "PHONE", POTA: "K-1234"}, "",
+		},
+		{
+			"implied POTA keywork ",
+			args{inputStr: "1230 oe6cud/p oe-00123", previousLine: LogLine{Mode: "FM", ModeType: "PHONE"}},
+			LogLine{Call: "OE6CUD/P", Time: "1230", ActualTime: "1230", RSTsent: "59", RSTrcvd: "59", Mode: "FM", ModeType: "PHONE", POTA: "OE-00123"}, "",
+		},
 		{
 			"date processing",
 			args{inputStr: "20.09.7 1230 oe6cud/p onff-0258", previousLine: LogLine{Mode: "FM", ModeType: "PHONE"}},
@@ -171,7 +289,7 @@ func TestParseLine(t *testing.T) {
 		{
 			"date processing - validation error",
 			args{inputStr: "20.09.34 1230 oe6cud/p onff-0258", previousLine: LogLine{Mode: "FM", ModeType: "PHONE"}},
-			LogLine{Date: "*2020-09-34", Call: "OE6CUD/P", Time: "1230", ActualTime: "1230", RSTsent: "59", RSTrcvd: "59", Mode: "FM", ModeType: "PHONE", WWFF: "ONFF-0258"}, "Error parsing time \"2020-09-34\": day out of range",
+			LogLine{Date: "2020-09-34", Call: "OE6CUD/P", Time: "1230", ActualTime: "1230", RSTsent: "59", RSTrcvd: "59", Mode: "FM", ModeType: "PHONE", WWFF: "ONFF-0258", InvalidFields: FieldSet(0).with(FieldDate, true)}, "parsing time \"2020-09-34\": day out of range",
 		},
 		{
 			"date processing - day ",
@@ -186,21 +304,41 @@ func TestParseLine(t *testing.T) {
 		{
 			"date processing - day (error) ",
 			args{inputStr: "day +++++++++++ 1230 oe6cud/p ", previousLine: LogLine{Date: "2020-09-05", Mode: "FM", ModeType: "PHONE"}},
-			LogLine{Date: "*2020-09-05", Call: "OE6CUD/P", Time: "1230", ActualTime: "1230", RSTsent: "59", RSTrcvd: "59", Mode: "FM", ModeType: "PHONE"}, "Invalid day increment, expecting smaller or equal to 10",
+			LogLine{Date: "2020-09-05", Call: "OE6CUD/P", Time: "1230", ActualTime: "1230", RSTsent: "59", RSTrcvd: "59", Mode: "FM", ModeType: "PHONE", InvalidFields: FieldSet(0).with(FieldDate, true)}, "Invalid day increment, expecting smaller or equal to 10",
 		},
 		{
 			"date band and mode on the same line)",
 			args{inputStr: "Date 20.09.7 40m cw 1230 oe6cud/p onff-0258", previousLine: LogLine{Mode: "FM", ModeType: "PHONE"}},
 			LogLine{Date: "2020-09-07", Mode: "CW", Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3, Call: "OE6CUD/P", Time: "1230", ActualTime: "1230", RSTsent: "599", RSTrcvd: "599", ModeType: "CW", WWFF: "ONFF-0258"}, "",
 		},
+		{
+			"contest serial sent and exchange received",
+			args{inputStr: "1839 wy7fd  ,033 .wy", previousLine: LogLine{Mode: "RTTY", ModeType: "CW"}},
+			LogLine{Call: "WY7FD", Time: "1839", ActualTime: "1839", RSTsent: "599", RSTrcvd: "599", Mode: "RTTY", ModeType: "CW", STX: "33", SRXstring: "WY"}, "",
+		},
+		{
+			"contest serial received",
+			args{inputStr: "nl7v .554", previousLine: LogLine{Mode: "RTTY", ModeType: "CW", STX: "33", SRXstring: "WY"}},
+			LogLine{Call: "NL7V", RSTsent: "599", RSTrcvd: "599", Mode: "RTTY", ModeType: "CW", SRX: "554"}, "",
+		},
+		{
+			"contest exchange strings (sent exchange is kept)",
+			args{inputStr: "26 e77dx .jn84", previousLine: LogLine{Time: "1924", Mode: "CW", ModeType: "CW", STXstring: "JN58", SRXstring: "JN69"}},
+			LogLine{Call: "E77DX", Time: "1926", ActualTime: "1926", RSTsent: "599", RSTrcvd: "599", Mode: "CW", ModeType: "CW", STXstring: "JN58", SRXstring: "JN84"}, "",
+		},
+		{
+			"contest exchange before the call",
+			args{inputStr: ".wy wy7fd", previousLine: LogLine{Mode: "CW", ModeType: "CW"}},
+			LogLine{Call: "WY7FD", RSTsent: "599", RSTrcvd: "599", Mode: "CW", ModeType: "CW"}, "Unable to make sense of [.wy].",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotLogLine, gotErrorMsg := ParseLine(tt.args.inputStr, tt.args.previousLine)
+			gotLogLine, gotDiagnostics := ParseLine(tt.args.inputStr, tt.args.previousLine)
 			if !reflect.DeepEqual(gotLogLine, tt.wantLogLine) {
 				t.Errorf("ParseLine() gotLogLine = %v, want %v", gotLogLine, tt.wantLogLine)
 			}
-			if gotErrorMsg != tt.wantErrorMsg {
+			if gotErrorMsg := diagnosticMessages(gotDiagnostics); gotErrorMsg != tt.wantErrorMsg {
 				t.Errorf("ParseLine() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
 			}
 		})
@@ -251,13 +389,26 @@ func TestHappyParseLine(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotLogLine, gotErrorMsg := ParseLine(tt.args.inputStr, tt.args.previousLine)
+			gotLogLine, gotDiagnostics := ParseLine(tt.args.inputStr, tt.args.previousLine)
 			if !reflect.DeepEqual(gotLogLine, tt.wantLogLine) {
 				t.Errorf("ParseLine() gotLogLine = %v, want %v", gotLogLine, tt.wantLogLine)
 			}
-			if gotErrorMsg != tt.wantErrorMsg {
+			if gotErrorMsg := diagnosticMessages(gotDiagnostics); gotErrorMsg != tt.wantErrorMsg {
 				t.Errorf("ParseLine() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
 			}
 		})
 	}
 }
+
+func TestParseLine_diagnostics(t *testing.T) {
+	previousLine := LogLine{Mode: "CW", ModeType: "CW", Band: "40m", BandLowerLimit: 7.0, BandUpperLimit: 7.3}
+	_, gotDiagnostics := ParseLine("0950 <comment> on4kjm #z9 14.045", previousLine)
+
+	wantDiagnostics := []Diagnostic{
+		{Column: 23, Length: 3, Code: CodeInvalidGrid, Severity: SeverityError, Message: "[z9] is an invalid grid reference"},
+		{Column: 27, Length: 6, Code: CodeInvalidFrequency, Severity: SeverityError, Message: "Frequency [14.045] is invalid for 40m band."},
+	}
+	if !reflect.DeepEqual(gotDiagnostics, wantDiagnostics) {
+		t.Errorf("ParseLine() gotDiagnostics = %v, want %v", gotDiagnostics, wantDiagnostics)
+	}
+}
@@ -0,0 +1,209 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var regexpLineComment = regexp.MustCompile("^[[:blank:]]*#")
+var regexpOnlySpaces = regexp.MustCompile("^\\s+$")
+var regexpSingleMultiLineComment = regexp.MustCompile("^[[:blank:]]*{.+}$")
+var regexpStartMultiLineComment = regexp.MustCompile("^[[:blank:]]*{")
+var regexpEndMultiLineComment = regexp.MustCompile("}$")
+
+//headerKeywords are the keywords starting a header statement
+var headerKeywords = []string{"mycall", "operator", "mywwff", "mysota", "mypota", "mygrid", "qslmsg", "nickname", "serial", "region"}
+
+//Parser reads a FLE log and produces its statements, one line at a time
+type Parser struct {
+	scanner       *bufio.Scanner
+	lineCount     int
+	isInMultiLine bool
+}
+
+//NewParser creates a parser reading the supplied input
+func NewParser(reader io.Reader) *Parser {
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanLines)
+	return &Parser{scanner: scanner}
+}
+
+//Next parses the next line of the input and returns its statement and the problems found by the parser.
+//isParsed is false at the end of the input or if the input could not be read (see Err).
+func (parser *Parser) Next() (statement Statement, diagnostics []Diagnostic, isParsed bool) {
+	if !parser.scanner.Scan() {
+		return nil, nil, false
+	}
+	parser.lineCount++
+	statement, diagnostics = parser.parseLine(parser.scanner.Text())
+	return statement, diagnostics, true
+}
+
+//Err returns the error encountered while reading the input, if any
+func (parser *Parser) Err() error {
+	return parser.scanner.Err()
+}
+
+//ParseFle parses a complete FLE log. The problems found by the parser are returned as diagnostics.
+//An error is only returned if the input could not be read.
+func ParseFle(reader io.Reader) (statements []Statement, diagnostics []Diagnostic, err error) {
+	parser := NewParser(reader)
+	for {
+		statement, lineDiagnostics, isParsed := parser.Next()
+		if !isParsed {
+			break
+		}
+		statements = append(statements, statement)
+		diagnostics = append(diagnostics, lineDiagnostics...)
+	}
+	return statements, diagnostics, parser.Err()
+}
+
+//parseLine identifies the kind of line (comment, header or data) and parses it
+func (parser *Parser) parseLine(line string) (Statement, []Diagnostic) {
+	lineNumber := parser.lineCount
+
+	switch {
+	case regexpLineComment.MatchString(line), len(line) == 0, regexpOnlySpaces.MatchString(line):
+		return CommentStatement{Line: lineNumber, Text: line}, nil
+	case regexpStartMultiLineComment.MatchString(line):
+		//a "multi-line" comment can fit on a single line
+		if !regexpSingleMultiLineComment.MatchString(line) {
+			parser.isInMultiLine = true
+		}
+		return CommentStatement{Line: lineNumber, Text: line}, nil
+	case parser.isInMultiLine:
+		if regexpEndMultiLineComment.MatchString(line) {
+			parser.isInMultiLine = false
+		}
+		return CommentStatement{Line: lineNumber, Text: line}, nil
+	}
+
+	if headerStatement, isHeader := parseHeaderLine(line, lineNumber); isHeader {
+		return headerStatement, nil
+	}
+
+	return parseDataLine(line, lineNumber)
+}
+
+//parseHeaderLine recognizes a header statement: a line starting with a header keyword followed by a space,
+//or a header keyword alone (without value)
+func parseHeaderLine(line string, lineNumber int) (statement HeaderStatement, isHeader bool) {
+	for _, keyword := range headerKeywords {
+		if strings.EqualFold(strings.TrimRight(line, " \t"), keyword) {
+			value := Token{Type: TokenWord, Line: lineNumber, Column: len(keyword) + 2}
+			return HeaderStatement{Line: lineNumber, Keyword: keyword, Value: value}, true
+		}
+		if len(line) > len(keyword) && strings.EqualFold(line[:len(keyword)], keyword) && line[len(keyword)] == ' ' {
+			value := Token{Type: TokenWord, Text: line[len(keyword)+1:], Line: lineNumber, Column: len(keyword) + 2}
+			value.Length = len(value.Text)
+			return HeaderStatement{Line: lineNumber, Keyword: keyword, Value: value}, true
+		}
+	}
+	return HeaderStatement{}, false
+}
+
+//parseDataLine parses a line of the data block. It is a QSO if it contains a call.
+func parseDataLine(line string, lineNumber int) (Statement, []Diagnostic) {
+	dataLine, diagnostics := parseDataElements(line, lineNumber)
+	for _, element := range dataLine.Elements {
+		if element.Type == TokenCall {
+			return QsoStatement{dataLine}, diagnostics
+		}
+	}
+	return ContextStatement{dataLine}, diagnostics
+}
+
+//parseDataElements cuts a data line in elements and resolves their type.
+//The line number is only used to locate the elements.
+func parseDataElements(line string, lineNumber int) (DataLine, []Diagnostic) {
+	elements, diagnostics := resolveElements(lexLine(line, lineNumber))
+	return DataLine{Line: lineNumber, Elements: elements}, diagnostics
+}
+
+//resolveElements sets the type of the elements whose meaning depends on their position in the line.
+//Left of the call, numbers are times (or frequencies in kHz if they can't be a time). Right of it, they are reports (sent, then received);
+//references and contest exchanges are only expected right of the call.
+func resolveElements(tokens []Token) (elements []Token, diagnostics []Diagnostic) {
+	isRightOfCall := false
+	haveSentReport := false
+	haveComment := false
+	haveQslMessage := false
+
+	for _, token := range tokens {
+		source := token.Source()
+		switch token.Type {
+		case TokenCall:
+			isRightOfCall = true
+		case TokenNumber:
+			switch {
+			case !isRightOfCall && (regexpIsFullTime.MatchString(token.Text) || regexpIsTimePart.MatchString(token.Text)):
+				token.Type = TokenTime
+			case !isRightOfCall && regexpIsKhzFreq.MatchString(token.Text):
+				token.Type = TokenFrequency
+			case isRightOfCall && regexpIsRst.MatchString(token.Text):
+				token.Type, haveSentReport = reportType(haveSentReport)
+			default:
+				token.Type = TokenUnknown
+			}
+		case TokenSignedNumber:
+			if isRightOfCall {
+				token.Type, haveSentReport = reportType(haveSentReport)
+			} else {
+				token.Type = TokenUnknown
+			}
+		case TokenSentExchange, TokenRcvdExchange, TokenWwffKeyword, TokenSotaKeyword, TokenPotaKeyword, TokenWwffRef, TokenSotaRef, TokenPotaRef:
+			if !isRightOfCall {
+				token.Type = TokenUnknown
+			}
+		case TokenComment:
+			if haveComment {
+				token.Type = TokenUnknown
+			}
+			haveComment = true
+		case TokenQslMessage:
+			if haveQslMessage {
+				token.Type = TokenUnknown
+			}
+			haveQslMessage = true
+		case TokenWord:
+			token.Type = TokenUnknown
+		}
+
+		if token.Type == TokenUnknown {
+			//keeps the brackets of a misplaced comment or QSL message
+			token.Text = source
+			diagnostic := newDiagnostic(CodeUnknownElement, "Unable to make sense of [%s].", source).at(token.Column, token.Length)
+			diagnostic.Line = token.Line
+			diagnostics = append(diagnostics, diagnostic)
+		}
+		elements = append(elements, token)
+	}
+	return elements, diagnostics
+}
+
+//reportType returns the type of a report: the first one is the sent report, the second one the received report
+func reportType(haveSentReport bool) (tokenType TokenType, isSentReportFound bool) {
+	if haveSentReport {
+		return TokenRcvdReport, true
+	}
+	return TokenSentReport, true
+}
@@ -0,0 +1,178 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFle(t *testing.T) {
+	input := "# Header\n" +
+		"mycall on4kjm/p\n" +
+		"MyWwff  onff-0258\n" +
+		"\n" +
+		"{ multi-line\n" +
+		"comment }\n" +
+		"date 2020-05-23 40m cw\n" +
+		"1310 ik5zve 5 <tnx>\n"
+
+	wantStatements := []Statement{
+		CommentStatement{Line: 1, Text: "# Header"},
+		HeaderStatement{Line: 2, Keyword: "mycall", Value: Token{Type: TokenWord, Text: "on4kjm/p", Line: 2, Column: 8, Length: 8}},
+		HeaderStatement{Line: 3, Keyword: "mywwff", Value: Token{Type: TokenWord, Text: " onff-0258", Line: 3, Column: 8, Length: 10}},
+		CommentStatement{Line: 4, Text: ""},
+		CommentStatement{Line: 5, Text: "{ multi-line"},
+		CommentStatement{Line: 6, Text: "comment }"},
+		ContextStatement{DataLine{Line: 7, Elements: []Token{
+			{Type: TokenDateKeyword, Text: "date", Line: 7, Column: 1, Length: 4},
+			{Type: TokenDate, Text: "2020-05-23", Line: 7, Column: 6, Length: 10},
+			{Type: TokenBand, Text: "40m", Line: 7, Column: 17, Length: 3},
+			{Type: TokenMode, Text: "cw", Line: 7, Column: 21, Length: 2},
+		}}},
+		QsoStatement{DataLine{Line: 8, Elements: []Token{
+			{Type: TokenTime, Text: "1310", Line: 8, Column: 1, Length: 4},
+			{Type: TokenCall, Text: "ik5zve", Line: 8, Column: 6, Length: 6},
+			{Type: TokenSentReport, Text: "5", Line: 8, Column: 13, Length: 1},
+			{Type: TokenComment, Text: "tnx", Line: 8, Column: 15, Length: 5},
+		}}},
+	}
+
+	gotStatements, gotDiagnostics, err := ParseFle(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseFle() unexpected error: %v", err)
+	}
+	if len(gotDiagnostics) != 0 {
+		t.Errorf("ParseFle() unexpected diagnostics: %v", gotDiagnostics)
+	}
+	if !reflect.DeepEqual(gotStatements, wantStatements) {
+		t.Errorf("ParseFle() gotStatements = %v, want %v", gotStatements, wantStatements)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("broken input")
+}
+
+func TestParseFle_readError(t *testing.T) {
+	if _, _, err := ParseFle(failingReader{}); err == nil {
+		t.Errorf("ParseFle() expected an error")
+	}
+}
+
+func Test_resolveElements(t *testing.T) {
+	tests := []struct {
+		name            string
+		line            string
+		wantTypes       []TokenType
+		wantDiagnostics []Diagnostic
+	}{
+		{
+			"time and reports",
+			"1310 ik5zve 5 57",
+			[]TokenType{TokenTime, TokenCall, TokenSentReport, TokenRcvdReport},
+			nil,
+		},
+		{
+			"dB reports",
+			"ik5zve -15 +03",
+			[]TokenType{TokenCall, TokenSentReport, TokenRcvdReport},
+			nil,
+		},
+		{
+			"dB report left of the call",
+			"-15 ik5zve",
+			[]TokenType{TokenUnknown, TokenCall},
+			[]Diagnostic{{Line: 4, Column: 1, Length: 3, Code: CodeUnknownElement, Severity: SeverityError, Message: "Unable to make sense of [-15]."}},
+		},
+		{
+			"frequency in kHz left of the call",
+			"7018 ik5zve",
+			[]TokenType{TokenFrequency, TokenCall},
+			nil,
+		},
+		{
+			"time that could be a frequency in kHz",
+			"1840 ik5zve",
+			[]TokenType{TokenTime, TokenCall},
+			nil,
+		},
+		{
+			"partial time",
+			"5 ik5zve",
+			[]TokenType{TokenTime, TokenCall},
+			nil,
+		},
+		{
+			"references and exchanges right of the call",
+			"ik5zve wwff onff-0258 ,012 .013",
+			[]TokenType{TokenCall, TokenWwffKeyword, TokenWwffRef, TokenSentExchange, TokenRcvdExchange},
+			nil,
+		},
+		{
+			"reference left of the call",
+			"onff-0258 ik5zve",
+			[]TokenType{TokenUnknown, TokenCall},
+			[]Diagnostic{{Line: 4, Column: 1, Length: 9, Code: CodeUnknownElement, Severity: SeverityError, Message: "Unable to make sense of [onff-0258]."}},
+		},
+		{
+			"third report",
+			"ik5zve 5 5 5",
+			[]TokenType{TokenCall, TokenSentReport, TokenRcvdReport, TokenRcvdReport},
+			nil,
+		},
+		{
+			"duplicated comment",
+			"ik5zve <bracketed text> bbbbb < double >",
+			[]TokenType{TokenCall, TokenComment, TokenUnknown, TokenUnknown},
+			[]Diagnostic{
+				{Line: 4, Column: 25, Length: 5, Code: CodeUnknownElement, Severity: SeverityError, Message: "Unable to make sense of [bbbbb]."},
+				{Line: 4, Column: 31, Length: 10, Code: CodeUnknownElement, Severity: SeverityError, Message: "Unable to make sense of [< double >]."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotElements, gotDiagnostics := resolveElements(lexLine(tt.line, 4))
+			var gotTypes []TokenType
+			for _, element := range gotElements {
+				gotTypes = append(gotTypes, element.Type)
+			}
+			if !reflect.DeepEqual(gotTypes, tt.wantTypes) {
+				t.Errorf("resolveElements() gotTypes = %v, want %v", gotTypes, tt.wantTypes)
+			}
+			if !reflect.DeepEqual(gotDiagnostics, tt.wantDiagnostics) {
+				t.Errorf("resolveElements() gotDiagnostics = %v, want %v", gotDiagnostics, tt.wantDiagnostics)
+			}
+		})
+	}
+}
+
+func TestQsoStatement_Call(t *testing.T) {
+	statement, _ := parseDataLine("1310 ik5zve 5", 1)
+	qso, isQso := statement.(QsoStatement)
+	if !isQso {
+		t.Fatalf("parseDataLine() = %T, want QsoStatement", statement)
+	}
+	if got := qso.Call().Text; got != "ik5zve" {
+		t.Errorf("QsoStatement.Call() = %v, want ik5zve", got)
+	}
+}
@@ -0,0 +1,57 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"strings"
+)
+
+//isS2S returns true if the QSO is a summit to summit contact (both stations on a summit)
+func isS2S(logLine LogLine) bool {
+	return logLine.MySOTA != "" && logLine.SOTA != ""
+}
+
+//selectS2sQsos returns the summit to summit QSOs of the log
+func selectS2sQsos(fullLog []LogLine) (s2sLog []LogLine) {
+	for _, logLine := range fullLog {
+		if isS2S(logLine) {
+			s2sLog = append(s2sLog, logLine)
+		}
+	}
+	return s2sLog
+}
+
+// Date, Time, band, mode, call, my summit, summit
+var s2sLineFormat = "%-10s %-4s %-4s %-4s %-12s %-12s %s\n"
+
+//SprintS2sReport lists the summit to summit QSOs found in the log
+func SprintS2sReport(fullLog []LogLine) string {
+	s2sLog := selectS2sQsos(fullLog)
+	if len(s2sLog) == 0 {
+		return "No summit to summit QSO found\n"
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Summit to summit QSOs (%d)\n\n", len(s2sLog)))
+	output.WriteString(fmt.Sprintf(s2sLineFormat, "Date", "Time", "Band", "Mode", "Call", "MySummit", "Summit"))
+	output.WriteString(fmt.Sprintf(s2sLineFormat, "----", "----", "----", "----", "----", "--------", "------"))
+	for _, logLine := range s2sLog {
+		output.WriteString(fmt.Sprintf(s2sLineFormat, logLine.Date, logLine.Time, logLine.Band, logLine.Mode, logLine.Call, logLine.MySOTA, logLine.SOTA))
+	}
+	return output.String()
+}
@@ -0,0 +1,51 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var sampleS2sLog = []LogLine{
+	{Date: "2020-05-24", MyCall: "ON4KJM/P", MySOTA: "ON/ON-001", Time: "1310", Band: "20m", Mode: "CW", Call: "S57LC"},
+	{Date: "2020-05-24", MyCall: "ON4KJM/P", MySOTA: "ON/ON-001", Time: "1312", Band: "20m", Mode: "CW", Call: "HB9ABC/P", SOTA: "HB/BE-001"},
+	{Date: "2020-05-24", MyCall: "ON4KJM", Time: "1420", Band: "40m", Mode: "CW", Call: "G4ELZ/P", SOTA: "G/LD-001"},
+}
+
+func Test_selectS2sQsos(t *testing.T) {
+	want := []LogLine{sampleS2sLog[1]}
+	if got := selectS2sQsos(sampleS2sLog); !reflect.DeepEqual(got, want) {
+		t.Errorf("selectS2sQsos() = %v, want %v", got, want)
+	}
+	if got := selectS2sQsos(sampleS2sLog[2:]); got != nil {
+		t.Errorf("selectS2sQsos() = %v, want nil", got)
+	}
+}
+
+func ExampleSprintS2sReport() {
+	fmt.Print(SprintS2sReport(sampleS2sLog))
+	fmt.Print(SprintS2sReport(sampleS2sLog[0:1]))
+	//Output:
+	//Summit to summit QSOs (1)
+	//
+	//Date       Time Band Mode Call         MySummit     Summit
+	//----       ---- ---- ---- ----         --------     ------
+	//2020-05-24 1312 20m  CW   HB9ABC/P     ON/ON-001    HB/BE-001
+	//No summit to summit QSO found
+}
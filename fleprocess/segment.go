@@ -0,0 +1,58 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+)
+
+//splitLogInSegments cuts the log in segments: a new segment starts each time one of the header values
+//(MyCall, Operator, MyWWFF, MySOTA, MyPOTA, MyGrid, Nickname) is redefined in the data block.
+//The segments share the underlying array of the full log.
+func splitLogInSegments(fullLog []LogLine) (segments [][]LogLine) {
+	segmentStart := 0
+	for i := 1; i < len(fullLog); i++ {
+		if !isSameHeader(fullLog[i-1], fullLog[i]) {
+			segments = append(segments, fullLog[segmentStart:i])
+			segmentStart = i
+		}
+	}
+	if len(fullLog) > 0 {
+		segments = append(segments, fullLog[segmentStart:])
+	}
+	return segments
+}
+
+//isSameHeader returns true if both log lines have the same header values
+func isSameHeader(a, b LogLine) bool {
+	return a.MyCall == b.MyCall &&
+		a.Operator == b.Operator &&
+		a.MyWWFF == b.MyWWFF &&
+		a.MySOTA == b.MySOTA &&
+		a.MyPOTA == b.MyPOTA &&
+		a.MyGrid == b.MyGrid &&
+		a.Nickname == b.Nickname
+}
+
+//segmentLocation returns a text locating the segment for a meaningfull error message.
+//Nothing is returned if the log contains a single segment.
+func segmentLocation(segmentCount, segmentIndex, firstEntry int) string {
+	if segmentCount < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" (segment #%d starting at log entry #%d)", segmentIndex+1, firstEntry+1)
+}
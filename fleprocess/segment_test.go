@@ -0,0 +1,60 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_splitLogInSegments(t *testing.T) {
+	sampleLog := []LogLine{
+		{MyCall: "ON4KJM/P", MySOTA: "ON/ON-001", Call: "S57LC"},
+		{MyCall: "ON4KJM/P", MySOTA: "ON/ON-001", Call: "ON4LY"},
+		{MyCall: "ON4KJM/P", MySOTA: "ON/ON-002", Call: "DL1ABC"},
+		{MyCall: "ON4KJM/P", MySOTA: "ON/ON-002", Operator: "ON4DO", Call: "F1XYZ"},
+	}
+
+	tests := []struct {
+		name         string
+		fullLog      []LogLine
+		wantSegments [][]LogLine
+	}{
+		{
+			"Empty log",
+			[]LogLine{},
+			nil,
+		},
+		{
+			"Single segment",
+			sampleLog[0:2],
+			[][]LogLine{sampleLog[0:2]},
+		},
+		{
+			"Several segments",
+			sampleLog,
+			[][]LogLine{sampleLog[0:2], sampleLog[2:3], sampleLog[3:4]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotSegments := splitLogInSegments(tt.fullLog); !reflect.DeepEqual(gotSegments, tt.wantSegments) {
+				t.Errorf("splitLogInSegments() = %v, want %v", gotSegments, tt.wantSegments)
+			}
+		})
+	}
+}
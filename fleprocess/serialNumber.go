@@ -0,0 +1,107 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//SerialNumbers holds the state of the consecutive (sent) contest serial numbers
+type SerialNumbers struct {
+	isEnabled bool
+	//first serial number to be used
+	start int
+	//if true, each band has its own counter
+	isPerBand bool
+	//next serial number to be used, indexed by band ("" when using a global counter)
+	nextSerial map[string]int
+}
+
+//defaultSerialStart is the first serial number when no start number is defined
+const defaultSerialStart = 1
+
+//isSerialCounterType returns true if the element is a counter type ("band" or "global")
+func isSerialCounterType(element string) bool {
+	return strings.EqualFold(element, "band") || strings.EqualFold(element, "global")
+}
+
+//parseSerialDefinition reads the value of the "serial" header keyword.
+//The syntax is "serial [<start>] [band|global]". If the start number is omitted, the numbering starts at 1.
+//If the counter type is omitted, a global counter is used.
+func parseSerialDefinition(definition string) (sn SerialNumbers, errorMsg string) {
+	elements := strings.Fields(definition)
+
+	start := defaultSerialStart
+	if len(elements) > 0 && !isSerialCounterType(elements[0]) {
+		var err error
+		if start, err = strconv.Atoi(elements[0]); err != nil || start < 0 {
+			return sn, "[" + elements[0] + "] is not a valid start serial number"
+		}
+		elements = elements[1:]
+	}
+
+	if len(elements) > 1 {
+		return sn, "expecting a start number optionally followed by \"band\" or \"global\""
+	}
+	isPerBand := false
+	if len(elements) == 1 {
+		if !isSerialCounterType(elements[0]) {
+			return sn, "[" + elements[0] + "] is an invalid counter type (expecting \"band\" or \"global\")"
+		}
+		isPerBand = strings.EqualFold(elements[0], "band")
+	}
+
+	sn = SerialNumbers{isEnabled: true, start: start, isPerBand: isPerBand, nextSerial: make(map[string]int)}
+	return sn, ""
+}
+
+//String displays the serial number settings
+func (sn *SerialNumbers) String() string {
+	if !sn.isEnabled {
+		return "disabled"
+	}
+	if sn.isPerBand {
+		return fmt.Sprintf("starting at %d (per band)", sn.start)
+	}
+	return fmt.Sprintf("starting at %d", sn.start)
+}
+
+//assignSerial sets the sent serial number (STX) of the supplied QSO and increments the counter.
+//A serial number that was explicitly entered in the log resets the counter to that value.
+func (sn *SerialNumbers) assignSerial(logLine *LogLine) {
+	if !sn.isEnabled {
+		return
+	}
+
+	counterKey := ""
+	if sn.isPerBand {
+		counterKey = logLine.Band
+	}
+
+	if logLine.STX != "" {
+		//The serial was defined in the log: resynchronise the counter on it
+		sn.nextSerial[counterKey], _ = strconv.Atoi(logLine.STX)
+	} else {
+		if _, ok := sn.nextSerial[counterKey]; !ok {
+			sn.nextSerial[counterKey] = sn.start
+		}
+		logLine.STX = strconv.Itoa(sn.nextSerial[counterKey])
+	}
+	sn.nextSerial[counterKey]++
+}
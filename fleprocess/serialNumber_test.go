@@ -0,0 +1,136 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseSerialDefinition(t *testing.T) {
+	tests := []struct {
+		name         string
+		definition   string
+		wantSn       SerialNumbers
+		wantErrorMsg string
+	}{
+		{
+			"Global counter (implicit)",
+			"1",
+			SerialNumbers{isEnabled: true, start: 1, isPerBand: false, nextSerial: map[string]int{}}, "",
+		},
+		{
+			"Global counter (explicit)",
+			" 33  Global ",
+			SerialNumbers{isEnabled: true, start: 33, isPerBand: false, nextSerial: map[string]int{}}, "",
+		},
+		{
+			"Per band counter",
+			"1 band",
+			SerialNumbers{isEnabled: true, start: 1, isPerBand: true, nextSerial: map[string]int{}}, "",
+		},
+		{
+			"Default start",
+			"",
+			SerialNumbers{isEnabled: true, start: 1, isPerBand: false, nextSerial: map[string]int{}}, "",
+		},
+		{
+			"Per band counter with default start",
+			" Band",
+			SerialNumbers{isEnabled: true, start: 1, isPerBand: true, nextSerial: map[string]int{}}, "",
+		},
+		{
+			"Start after the counter type",
+			"band 1",
+			SerialNumbers{}, "expecting a start number optionally followed by \"band\" or \"global\"",
+		},
+		{
+			"Invalid start",
+			"one",
+			SerialNumbers{}, "[one] is not a valid start serial number",
+		},
+		{
+			"Invalid counter type",
+			"1 mode",
+			SerialNumbers{}, "[mode] is an invalid counter type (expecting \"band\" or \"global\")",
+		},
+		{
+			"Too many elements",
+			"1 band global",
+			SerialNumbers{}, "expecting a start number optionally followed by \"band\" or \"global\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSn, gotErrorMsg := parseSerialDefinition(tt.definition)
+			if !reflect.DeepEqual(gotSn, tt.wantSn) {
+				t.Errorf("parseSerialDefinition() gotSn = %v, want %v", gotSn, tt.wantSn)
+			}
+			if gotErrorMsg != tt.wantErrorMsg {
+				t.Errorf("parseSerialDefinition() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
+			}
+		})
+	}
+}
+
+func TestSerialNumbers_assignSerial(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		input      []LogLine
+		wantSTX    []string
+	}{
+		{
+			"Global counter",
+			"1",
+			[]LogLine{{Band: "20m"}, {Band: "15m"}, {Band: "20m"}},
+			[]string{"1", "2", "3"},
+		},
+		{
+			"Per band counter",
+			"1 band",
+			[]LogLine{{Band: "20m"}, {Band: "15m"}, {Band: "20m"}},
+			[]string{"1", "1", "2"},
+		},
+		{
+			"Counter resynchronised by an explicit serial",
+			"1",
+			[]LogLine{{Band: "20m"}, {Band: "20m", STX: "33"}, {Band: "20m"}},
+			[]string{"1", "33", "34"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sn, _ := parseSerialDefinition(tt.definition)
+			for i, logLine := range tt.input {
+				sn.assignSerial(&logLine)
+				if logLine.STX != tt.wantSTX[i] {
+					t.Errorf("assignSerial() QSO #%d got STX = %v, want %v", i+1, logLine.STX, tt.wantSTX[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSerialNumbers_assignSerial_disabled(t *testing.T) {
+	sn := SerialNumbers{}
+	logLine := LogLine{Band: "20m"}
+	sn.assignSerial(&logLine)
+	if logLine.STX != "" {
+		t.Errorf("assignSerial() should not number QSOs when disabled (got %s)", logLine.STX)
+	}
+}
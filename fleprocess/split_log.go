@@ -0,0 +1,106 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//outputLog is a part of the loaded log that is written to its own output file
+type outputLog struct {
+	reference      string
+	outputFilename string
+	log            []LogLine
+}
+
+//refSelector returns the activation reference(s) of a QSO used to split the log
+type refSelector func(logLine LogLine) string
+
+func selectMyWWFF(logLine LogLine) string { return logLine.MyWWFF }
+func selectMySOTA(logLine LogLine) string { return logLine.MySOTA }
+func selectMyPOTA(logLine LogLine) string { return logLine.MyPOTA }
+
+//buildOutputDirectory determines the directory where the split files are written.
+//If no output is specified, the directory of the input file is used.
+func buildOutputDirectory(output string, input string) (string, error) {
+	//validate that input is populated (should never happen if properly called)
+	if input == "" {
+		return "", fmt.Errorf("Unexepected error: no input file provided")
+	}
+
+	if output == "" {
+		return filepath.Dir(input), nil
+	}
+
+	info, err := os.Stat(output)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("Error: when splitting the output, the specified output must be an existing directory")
+	}
+	return output, nil
+}
+
+//splitLogPerActivation groups the QSOs per activation (call, selected references and UTC date).
+//The name of each file is built from these values, following the WWFF convention (e.g. ON4KJM@ONFF-025920200524.adi).
+func splitLogPerActivation(fullLog []LogLine, outputDirectory, extension string, selectors []refSelector) (activationLogs []outputLog) {
+	//position of the activation in activationLogs
+	activationIndex := make(map[string]int)
+
+	for _, logLine := range fullLog {
+		var refs []string
+		for _, selector := range selectors {
+			if ref := selector(logLine); ref != "" {
+				refs = append(refs, ref)
+			}
+		}
+		reference := strings.Join(refs, "_")
+		date := adifDate(logLine.Date)
+
+		var filename string
+		if reference == "" {
+			filename = sanitizeFilename(logLine.MyCall) + "_" + date
+		} else {
+			filename = sanitizeFilename(logLine.MyCall) + "@" + sanitizeFilename(reference) + date
+		}
+
+		index, ok := activationIndex[filename]
+		if !ok {
+			index = len(activationLogs)
+			activationIndex[filename] = index
+			activationLogs = append(activationLogs, outputLog{reference: reference, outputFilename: filepath.Join(outputDirectory, filename+extension)})
+		}
+		activationLogs[index].log = append(activationLogs[index].log, logLine)
+	}
+	return activationLogs
+}
+
+//sanitizeFilename replaces the characters of a call or reference that can't be used in a file name
+func sanitizeFilename(value string) string {
+	return strings.NewReplacer("/", "-", ",", "_", " ", "").Replace(value)
+}
+
+//verifyOutputFilenames checks (and eventually protects against overwrite) all the output files before anything is written
+func verifyOutputFilenames(outputLogs []outputLog, inputFilename string, isOverwrite bool, extension string) (err error) {
+	for i := range outputLogs {
+		if outputLogs[i].outputFilename, err = buildOutputFilename(outputLogs[i].outputFilename, inputFilename, isOverwrite, extension); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package fleprocess
+
+/*
+Copyright © 2020 Jean-Marc Meessen, ON4KJM <on4kjm@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_splitLogPerActivation(t *testing.T) {
+	sampleLog := []LogLine{
+		{Date: "2020-05-24", MyCall: "ON4KJM/P", MyWWFF: "ONFF-0259", MySOTA: "ON/ON-001", Call: "S57LC"},
+		{Date: "2020-05-24", MyCall: "ON4KJM/P", MyWWFF: "ONFF-0259", MySOTA: "ON/ON-001", Call: "OK2CQR"},
+		{Date: "2020-05-25", MyCall: "ON4KJM/P", MyWWFF: "ONFF-0259", MySOTA: "ON/ON-001", Call: "G4ELZ"},
+		{Date: "2020-05-25", MyCall: "ON4KJM/P", MyWWFF: "ONFF-0001", MySOTA: "ON/ON-002", Call: "DL1ABC"},
+	}
+
+	type args struct {
+		fullLog   []LogLine
+		selectors []refSelector
+	}
+	tests := []struct {
+		name               string
+		args               args
+		wantActivationLogs []outputLog
+	}{
+		{
+			"WWFF activations",
+			args{fullLog: sampleLog, selectors: []refSelector{selectMyWWFF}},
+			[]outputLog{
+				{reference: "ONFF-0259", outputFilename: filepath.Join("out", "ON4KJM-P@ONFF-025920200524.adi"), log: sampleLog[0:2]},
+				{reference: "ONFF-0259", outputFilename: filepath.Join("out", "ON4KJM-P@ONFF-025920200525.adi"), log: sampleLog[2:3]},
+				{reference: "ONFF-0001", outputFilename: filepath.Join("out", "ON4KJM-P@ONFF-000120200525.adi"), log: sampleLog[3:4]},
+			},
+		},
+		{
+			"WWFF and SOTA activations",
+			args{fullLog: sampleLog[0:1], selectors: []refSelector{selectMyWWFF, selectMySOTA}},
+			[]outputLog{
+				{reference: "ONFF-0259_ON/ON-001", outputFilename: filepath.Join("out", "ON4KJM-P@ONFF-0259_ON-ON-00120200524.adi"), log: sampleLog[0:1]},
+			},
+		},
+		{
+			"No reference",
+			args{fullLog: sampleLog[0:1], selectors: []refSelector{selectMyPOTA}},
+			[]outputLog{
+				{reference: "", outputFilename: filepath.Join("out", "ON4KJM-P_20200524.adi"), log: sampleLog[0:1]},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotActivationLogs := splitLogPerActivation(tt.args.fullLog, "out", ".adi", tt.args.selectors)
+			if !reflect.DeepEqual(gotActivationLogs, tt.wantActivationLogs) {
+				t.Errorf("splitLogPerActivation() = %v, want %v", gotActivationLogs, tt.wantActivationLogs)
+			}
+		})
+	}
+}
+
+func Test_buildOutputDirectory(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"no input", "", "", "", true},
+		{"no output", "", "../test/data/fle-1.txt", "../test/data", false},
+		{"output is a directory", "../test", "../test/data/fle-1.txt", "../test", false},
+		{"output is a file", "../test/data/fle-1.txt", "../test/data/fle-1.txt", "", true},
+		{"output does not exist", "../test/doesNotExist", "../test/data/fle-1.txt", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildOutputDirectory(tt.output, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("buildOutputDirectory() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("buildOutputDirectory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessAdifCommand_split(t *testing.T) {
+	outputDir, err := os.MkdirTemp("", "fle_split")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outputDir)
+
+	adifParams := AdifParams{InputFilename: "../test/data/ON4KJM@ONFF-025920200524.txt", OutputFilename: outputDir, IsWWFFcli: true, IsSplit: true}
+	if err := ProcessAdifCommand(adifParams); err != nil {
+		t.Fatalf("ProcessAdifCommand() unexpected error = %v", err)
+	}
+	expectedFile := filepath.Join(outputDir, "ON4KJM-P@ONFF-025920200524.adi")
+	if _, err := os.Stat(expectedFile); err != nil {
+		t.Errorf("ProcessAdifCommand() did not generate %s", expectedFile)
+	}
+
+	//A second run must not overwrite the existing file
+	if err := ProcessAdifCommand(adifParams); err == nil {
+		t.Errorf("ProcessAdifCommand() expected an error when the file already exists")
+	}
+	adifParams.IsOverwrite = true
+	if err := ProcessAdifCommand(adifParams); err != nil {
+		t.Errorf("ProcessAdifCommand() unexpected error with overwrite = %v", err)
+	}
+}
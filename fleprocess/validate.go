@@ -27,37 +27,92 @@ var validSotaRegexp = regexp.MustCompile(`^[0-9A-Z]{1,3}/[A-Z]{2}-[\d]{3}$`)
 
 // ValidateSota verifies whether the supplied string is a valid SOTA reference.
 // The syntax is: AA/NN-CCC: Association/Name-3-digit numeric Code (e.g. G/CE-001).
-func ValidateSota(inputStr string) (ref, errorMsg string) {
+// The reference is returned in uppercase, even if a diagnostic is generated.
+func ValidateSota(inputStr string) (ref string, diagnostic *Diagnostic) {
 	inputStr = strings.ToUpper(strings.TrimSpace(inputStr))
-	wrongInputStr := "*" + inputStr
 	if validSotaRegexp.MatchString(inputStr) {
-		return inputStr, ""
+		return inputStr, nil
 	}
-	errorMsg = "[" + inputStr + "] is an invalid SOTA reference"
-	return wrongInputStr, errorMsg
+	return inputStr, newDiagnostic(CodeInvalidSota, "[%s] is an invalid SOTA reference", inputStr)
 }
 
 var validWwffRegexp = regexp.MustCompile(`^[\d]{0,1}[A-Z]{1,2}FF-[\d]{4}$`)
 
 // ValidateWwff verifies whether the supplied string is a valid WWFF reference.
 // The syntax is: AAFF-CCCC: AA = national prefix, CCCC = 4-digit numeric code (e.g. ONFF-0001).
-func ValidateWwff(inputStr string) (ref, errorMsg string) {
+func ValidateWwff(inputStr string) (ref string, diagnostic *Diagnostic) {
 	inputStr = strings.ToUpper(strings.TrimSpace(inputStr))
-	wrongInputStr := "*" + inputStr
 	if validWwffRegexp.MatchString(inputStr) {
-		return inputStr, ""
+		return inputStr, nil
 	}
-	errorMsg = "[" + inputStr + "] is an invalid WWFF reference"
-	return wrongInputStr, errorMsg
+	return inputStr, newDiagnostic(CodeInvalidWwff, "[%s] is an invalid WWFF reference", inputStr)
+}
+
+var validPotaRegexp = regexp.MustCompile(`^[\d]{0,1}[A-Z]{1,2}-[\d]{4,5}$`)
+
+// ValidatePota verifies whether the supplied string is a valid POTA reference.
+// The syntax is: AA-CCCCC: AA = national prefix, CCCCC = 4 or 5-digit numeric code (e.g. ON-00001, K-1234).
+func ValidatePota(inputStr string) (ref string, diagnostic *Diagnostic) {
+	inputStr = strings.ToUpper(strings.TrimSpace(inputStr))
+	if validPotaRegexp.MatchString(inputStr) {
+		return inputStr, nil
+	}
+	return inputStr, newDiagnostic(CodeInvalidPota, "[%s] is an invalid POTA reference", inputStr)
+}
+
+var splitRefListRegexp = regexp.MustCompile(`[,\s]+`)
+
+// ValidateWwffList verifies a list of WWFF references (separated by commas or spaces) as used
+// for multi-reference (n-fer) activations. The references are returned as a comma separated list.
+func ValidateWwffList(inputStr string) (refList string, diagnostic *Diagnostic) {
+	return validateRefList(inputStr, ValidateWwff)
+}
+
+// ValidatePotaList verifies a list of POTA references (separated by commas or spaces) as used
+// for multi-reference (n-fer) activations. The references are returned as a comma separated list.
+func ValidatePotaList(inputStr string) (refList string, diagnostic *Diagnostic) {
+	return validateRefList(inputStr, ValidatePota)
+}
+
+// validateRefList checks every reference of the list with the supplied validation function.
+// The problems found are combined in a single diagnostic carrying the code of the first one.
+func validateRefList(inputStr string, validate func(string) (string, *Diagnostic)) (refList string, diagnostic *Diagnostic) {
+	var refs []string
+	for _, element := range splitRefListRegexp.Split(strings.TrimSpace(inputStr), -1) {
+		if element == "" {
+			continue
+		}
+		ref, refDiagnostic := validate(element)
+		if refDiagnostic == nil && isInList(ref, refs) {
+			refDiagnostic = newDiagnostic(CodeDuplicateReference, "[%s] is listed twice", ref)
+		}
+		if refDiagnostic != nil {
+			if diagnostic == nil {
+				diagnostic = refDiagnostic
+			} else {
+				diagnostic.Message = diagnostic.Message + ", " + refDiagnostic.Message
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return strings.Join(refs, ","), diagnostic
+}
+
+// splitRefList returns the individual references of a comma separated list
+func splitRefList(refList string) []string {
+	if refList == "" {
+		return nil
+	}
+	return strings.Split(refList, ",")
 }
 
 var validGridRegexp = regexp.MustCompile("(?i)^[a-z]{2}[0-9]{2}([a-z]{2})?$")
 
 // ValidateGridLocator verifies that the supplied is a valid Maidenhead locator reference
 // (either in 4 or 6 position). The returned grid case is normalized (first two letters
-// in uppercase, last pair in lowercase). If the grid is not valid, the supicious string
-// is prefixed with a * and an erroMsg is genrated.
-func ValidateGridLocator(grid string) (processedGrid, errorMsg string) {
+// in uppercase, last pair in lowercase). If the grid is not valid, it is returned
+// unchanged and a diagnostic is generated.
+func ValidateGridLocator(grid string) (processedGrid string, diagnostic *Diagnostic) {
 	if validGridRegexp.MatchString(grid) {
 		var output strings.Builder
 		for i, c := range grid {
@@ -74,12 +129,10 @@ func ValidateGridLocator(grid string) (processedGrid, errorMsg string) {
 				output.WriteString(strings.ToLower(string(c)))
 			}
 		}
-		return output.String(), ""
+		return output.String(), nil
 	}
 
-	processedGrid = "*" + grid
-	errorMsg = "[" + grid + "] is an invalid grid reference"
-	return processedGrid, errorMsg
+	return grid, newDiagnostic(CodeInvalidGrid, "[%s] is an invalid grid reference", grid)
 }
 
 var validCallRegexp = regexp.MustCompile(`[\d]{0,1}[A-Z]{1,2}\d([A-Z]{1,4}|\d{3,3}|\d{1,3}[A-Z])[A-Z]{0,5}`)
@@ -87,51 +140,51 @@ var validPrefixRegexp = regexp.MustCompile(`\A[a-zA-Z0-9]{1,3}$`)
 
 // ValidateCall verifies whether the supplied string is a valid callsign.
 // prefix and suffix are not checked for validity
-// If it is not valid, the supicious string is prefixed with a * and an erroMsg is genrated.
-func ValidateCall(sign string) (call, errorMsg string) {
+// If it is not valid, the (uppercase) string is returned with a diagnostic.
+func ValidateCall(sign string) (call string, diagnostic *Diagnostic) {
 	sign = strings.ToUpper(strings.TrimSpace(sign))
 	sp := strings.Split(sign, "/")
-	wrongSign := "*" + sign
 	switch len(sp) {
 	case 1:
 		if validCallRegexp.MatchString(sign) {
-			return sign, ""
+			return sign, nil
 		}
-		return wrongSign, "[" + sign + "] is an invalid call"
+		return sign, newDiagnostic(CodeInvalidCall, "[%s] is an invalid call", sign)
 	case 2:
 		// some ambiguity here we need to resolve, could be a prefix or a suffix
 		if validCallRegexp.MatchString(sp[0]) {
 			//Callisign with suffix (unchecked)
-			return sign, ""
+			return sign, nil
 		}
 		//else we are dealing with a prefixed Callsign
 		//validate the part that should contain the call (sp[1])
 		if !validCallRegexp.MatchString(sp[1]) {
-			return wrongSign, "[" + sp[1] + "] is an invalid call"
+			return sign, newDiagnostic(CodeInvalidCall, "[%s] is an invalid call", sp[1])
 		}
 		//validate the prefix
 		if !validPrefixRegexp.MatchString(sp[0]) {
-			return wrongSign, "[" + sp[0] + "] is an invalid prefix"
+			return sign, newDiagnostic(CodeInvalidCall, "[%s] is an invalid prefix", sp[0])
 		}
-		return sign, ""
+		return sign, nil
 	case 3:
 		//validate the part that should contain the call (sp[1])
 		if !validCallRegexp.MatchString(sp[1]) {
-			return wrongSign, "[" + sp[1] + "] is an invalid call"
+			return sign, newDiagnostic(CodeInvalidCall, "[%s] is an invalid call", sp[1])
 		}
 		//validate the prefix
 		if !validPrefixRegexp.MatchString(sp[0]) {
-			return wrongSign, "[" + sp[0] + "] is an invalid prefix"
+			return sign, newDiagnostic(CodeInvalidCall, "[%s] is an invalid prefix", sp[0])
 		}
 		//We don't check the suffix
-		return sign, ""
+		return sign, nil
 	}
-	return wrongSign, "[" + sign + "] is invalid: too many '/'"
+	return sign, newDiagnostic(CodeInvalidCall, "[%s] is invalid: too many '/'", sign)
 }
 
 var splitDateRegexp = regexp.MustCompile(`[-/ .]`)
 
-//NormalizeDate takes what looks like a date and normalises it to "YYYY-MM-DD"
+//NormalizeDate takes what looks like a date and normalises it to "YYYY-MM-DD".
+//If it can't, the input is returned unchanged with an error message.
 func NormalizeDate(inputStr string) (date, errorMsg string) {
 	//Try to split the string
 	s := splitDateRegexp.Split(inputStr, 4)
@@ -139,7 +192,7 @@ func NormalizeDate(inputStr string) (date, errorMsg string) {
 	//we should have three and only three elements
 	if i := len(s); i != 3 {
 		errorMsg = fmt.Sprintf("Bad date format: found %d elements while expecting 3.", i)
-		return "*" + inputStr, errorMsg
+		return inputStr, errorMsg
 	}
 
 	//complete the numbers if shorter than expected ("20" for the first and "0" for the two next)
@@ -150,7 +203,7 @@ func NormalizeDate(inputStr string) (date, errorMsg string) {
 	//This test is not really necessary, but rather belt and suspenders
 	if len(year) != 4 {
 		errorMsg = "Bad date format: first part doesn't look like a year"
-		return "*" + inputStr, errorMsg
+		return inputStr, errorMsg
 	}
 
 	month := s[1]
@@ -159,7 +212,7 @@ func NormalizeDate(inputStr string) (date, errorMsg string) {
 	}
 	if len(month) != 2 {
 		errorMsg = "Bad date format: second part doesn't look like a month"
-		return "*" + inputStr, errorMsg
+		return inputStr, errorMsg
 	}
 
 	day := s[2]
@@ -168,7 +221,7 @@ func NormalizeDate(inputStr string) (date, errorMsg string) {
 	}
 	if len(day) != 2 {
 		errorMsg = "Bad date format: third element doesn't look like a day"
-		return "*" + inputStr, errorMsg
+		return inputStr, errorMsg
 	}
 
 	//re-assemble the string with the correct delimiter
@@ -178,37 +231,37 @@ func NormalizeDate(inputStr string) (date, errorMsg string) {
 }
 
 // ValidateDate verifies whether the string is a valid date (YYYY-MM-DD).
-func ValidateDate(inputStr string) (ref, errorMsg string) {
+func ValidateDate(inputStr string) (ref string, diagnostic *Diagnostic) {
 
 	const RFC3339FullDate = "2006-01-02"
 
 	inputStr = strings.ToUpper(strings.TrimSpace(inputStr))
-	wrongInputStr := "*" + inputStr
 	_, err := time.Parse(RFC3339FullDate, inputStr)
 
 	if err == nil {
-		return inputStr, ""
+		return inputStr, nil
 	}
 
-	return wrongInputStr, fmt.Sprint(err)
+	return inputStr, newDiagnostic(CodeInvalidDate, "%s", err)
 }
 
 //IncrementDate will increment the supplied date by the specified increment. It returns the new date.
+//If the increment is invalid, the supplied date is returned with an error message.
 func IncrementDate(date string, increment int) (newdate string, err string) {
 	if date == "" {
 		return "", "No date to increment"
 	}
 	if increment < 1 {
-		return "*" + date, "Invalid day increment, expecting greater or equal to 1"
+		return date, "Invalid day increment, expecting greater or equal to 1"
 	}
 	if 10 < increment {
-		return "*" + date, "Invalid day increment, expecting smaller or equal to 10"
+		return date, "Invalid day increment, expecting smaller or equal to 10"
 	}
 
 	const RFC3339FullDate = "2006-01-02"
 	convertedTime, timeErr := time.Parse(RFC3339FullDate, date)
 	if timeErr != nil {
-		return "*" + date, "(Internal error) error " + fmt.Sprint(timeErr)
+		return date, "(Internal error) error " + fmt.Sprint(timeErr)
 	}
 	// the number of days specified in increment
 	newDate := convertedTime.AddDate(0, 0, increment)
@@ -216,87 +269,15 @@ func IncrementDate(date string, increment int) (newdate string, err string) {
 	return newDate.Format(RFC3339FullDate), ""
 }
 
-//IsBand retuns true if the passed input string is a valid string
+//IsBand retuns true if the passed input string is a valid string. The limits are the ones of the ADIF band plan,
+//altBandName is the band name used in the SOTA CSV file.
 func IsBand(inputStr string) (result bool, lowerLimit, upperLimit float64, altBandName string) {
-	switch strings.ToLower(inputStr) {
-	case "2190m":
-		return true, 0.1357, 0.1378, "tbd"
-	case "630m":
-		return true, 0.472, 0.479, "tbd"
-	case "560m":
-		return true, 0.501, 0.504, "tbd"
-	case "160m":
-		return true, 1.8, 2.0, "1.8MHz"
-	case "80m":
-		return true, 3.5, 4.0, "3.5MHz"
-	case "60m":
-		return true, 5.06, 5.45, "5MHz"
-	case "40m":
-		return true, 7.0, 7.3, "7MHz"
-	case "30m":
-		return true, 10.1, 10.15, "10MHz"
-	case "20m":
-		return true, 14.0, 14.35, "14MHz"
-	case "17m":
-		return true, 18.068, 18.168, "18MHz"
-	case "15m":
-		return true, 21.0, 21.45, "21MHz"
-	case "12m":
-		return true, 24.890, 24.99, "24MHz"
-	case "10m":
-		return true, 28.0, 29.7, "28MHz"
-	case "6m":
-		return true, 50, 54, "50MHz"
-	case "4m":
-		return true, 70, 71, "70MHz"
-	case "2m":
-		return true, 144, 148, "144MHz"
-	case "1.25m":
-		return true, 222, 225, "222MHz"
-	case "70cm":
-		return true, 420, 450, "432MHz"
-	case "33cm":
-		return true, 902, 928, "tbd"
-	case "23cm":
-		return true, 1240, 1300, "tbd"
-	case "13cm":
-		return true, 2300, 2450, "tbd"
-	case "9cm":
-		return true, 3300, 3500, "tbd"
-	case "6cm":
-		return true, 5650, 5925, "tbd"
-	case "3cm":
-		return true, 10000, 10500, "tbd"
-	case "1.25cm":
-		return true, 24000, 24250, "tbd"
-	case "6mm":
-		return true, 47000, 47200, "tbd"
-	case "4mm":
-		return true, 75500, 81000, "tbd"
-	case "2.5mm":
-		return true, 119980, 120020, "tbd"
-	case "2mm":
-		return true, 142000, 149000, "tbd"
-	case "1mm":
-		return true, 241000, 250000, "tbd"
-	}
-	return false, 0, 0, ""
+	band, isKnown := bandPlans[""][strings.ToLower(inputStr)]
+	return isKnown, band.LowerLimit, band.UpperLimit, band.SotaName
 }
 
+//getDefaultReport returns the type of the mode (CW, PHONE or DIGITAL) and the report used when none is entered
 func getDefaultReport(mode string) (modeType, defaultReport string) {
-	modeType = ""
-	defaultReport = ""
-
-	switch mode {
-	case "SSB", "AM", "FM":
-		modeType = "PHONE"
-		defaultReport = "59"
-	case "CW", "RTTY", "PSK":
-		modeType = "CW"
-		defaultReport = "599"
-	case "JT65", "JT9", "JT6M", "JT4", "JT44", "FSK441", "FT8", "ISCAT", "MSK144", "QRA64", "T10", "WSPR":
-		modeType = "DIGITAL"
-		defaultReport = "-10"
-	}
-	return modeType, defaultReport
+	modeType = fleModes[mode].ModeType
+	return modeType, defaultReports[modeType]
 }
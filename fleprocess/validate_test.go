@@ -32,37 +32,143 @@ func TestValidateWwff(t *testing.T) {
 		{
 			"Bad ref (no country prefix)",
 			args{inputStr: "ff-0258"},
-			"*FF-0258", "[FF-0258] is an invalid WWFF reference",
+			"FF-0258", "[FF-0258] is an invalid WWFF reference",
 		},
 		{
 			"Bad ref (wrong separator)",
 			args{inputStr: "gff/0258"},
-			"*GFF/0258", "[GFF/0258] is an invalid WWFF reference",
+			"GFF/0258", "[GFF/0258] is an invalid WWFF reference",
 		},
 		{
 			"Bad ref (reference too short)",
 			args{inputStr: "onff-258"},
-			"*ONFF-258", "[ONFF-258] is an invalid WWFF reference",
+			"ONFF-258", "[ONFF-258] is an invalid WWFF reference",
 		},
 		{
 			"Bad ref (no country prefix)",
 			args{inputStr: "onff-02589"},
-			"*ONFF-02589", "[ONFF-02589] is an invalid WWFF reference",
+			"ONFF-02589", "[ONFF-02589] is an invalid WWFF reference",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRef, gotErrorMsg := ValidateWwff(tt.args.inputStr)
+			gotRef, gotDiagnostic := ValidateWwff(tt.args.inputStr)
 			if gotRef != tt.wantRef {
 				t.Errorf("ValidateWwff() gotRef = %v, want %v", gotRef, tt.wantRef)
 			}
-			if gotErrorMsg != tt.wantErrorMsg {
+			if gotErrorMsg := diagnosticMessage(gotDiagnostic); gotErrorMsg != tt.wantErrorMsg {
 				t.Errorf("ValidateWwff() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
 			}
 		})
 	}
 }
 
+func TestValidatePota(t *testing.T) {
+	type args struct {
+		inputStr string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantRef      string
+		wantErrorMsg string
+	}{
+		{
+			"Good ref (simple)",
+			args{inputStr: "on-00259"},
+			"ON-00259", "",
+		},
+		{
+			"Good ref (single letter country, 4 digits)",
+			args{inputStr: "k-1234"},
+			"K-1234", "",
+		},
+		{
+			"Good ref (Numerical country)",
+			args{inputStr: "4x-0258"},
+			"4X-0258", "",
+		},
+		{
+			"Bad ref (no country prefix)",
+			args{inputStr: "-0258"},
+			"-0258", "[-0258] is an invalid POTA reference",
+		},
+		{
+			"Bad ref (WWFF reference)",
+			args{inputStr: "onff-0258"},
+			"ONFF-0258", "[ONFF-0258] is an invalid POTA reference",
+		},
+		{
+			"Bad ref (reference too short)",
+			args{inputStr: "us-258"},
+			"US-258", "[US-258] is an invalid POTA reference",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRef, gotDiagnostic := ValidatePota(tt.args.inputStr)
+			if gotRef != tt.wantRef {
+				t.Errorf("ValidatePota() gotRef = %v, want %v", gotRef, tt.wantRef)
+			}
+			if gotErrorMsg := diagnosticMessage(gotDiagnostic); gotErrorMsg != tt.wantErrorMsg {
+				t.Errorf("ValidatePota() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
+			}
+		})
+	}
+}
+
+func TestValidateRefList(t *testing.T) {
+	tests := []struct {
+		name         string
+		inputStr     string
+		validate     func(string) (string, *Diagnostic)
+		wantRefList  string
+		wantErrorMsg string
+	}{
+		{
+			"Single WWFF reference",
+			" onff-0258 ",
+			ValidateWwff,
+			"ONFF-0258", "",
+		},
+		{
+			"WWFF references separated by commas",
+			"onff-0258,onff-0259",
+			ValidateWwff,
+			"ONFF-0258,ONFF-0259", "",
+		},
+		{
+			"POTA references separated by spaces and commas",
+			"k-1234, k-4567  k-0001",
+			ValidatePota,
+			"K-1234,K-4567,K-0001", "",
+		},
+		{
+			"Invalid reference in the list",
+			"onff-0258 onff-25",
+			ValidateWwff,
+			"ONFF-0258,ONFF-25", "[ONFF-25] is an invalid WWFF reference",
+		},
+		{
+			"Duplicate reference",
+			"k-1234 K-1234",
+			ValidatePota,
+			"K-1234,K-1234", "[K-1234] is listed twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRefList, gotDiagnostic := validateRefList(tt.inputStr, tt.validate)
+			if gotRefList != tt.wantRefList {
+				t.Errorf("validateRefList() gotRefList = %v, want %v", gotRefList, tt.wantRefList)
+			}
+			if gotErrorMsg := diagnosticMessage(gotDiagnostic); gotErrorMsg != tt.wantErrorMsg {
+				t.Errorf("validateRefList() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
+			}
+		})
+	}
+}
+
 func TestValidateSota(t *testing.T) {
 	type args struct {
 		inputStr string
@@ -96,46 +202,46 @@ func TestValidateSota(t *testing.T) {
 		{
 			"Bad ref (long prefix)",
 			args{inputStr: "xxxx/ON-001"},
-			"*XXXX/ON-001", "[XXXX/ON-001] is an invalid SOTA reference",
+			"XXXX/ON-001", "[XXXX/ON-001] is an invalid SOTA reference",
 		},
 		{
 			"Bad ref (missing slash)",
 			args{inputStr: "on ON-001"},
-			"*ON ON-001", "[ON ON-001] is an invalid SOTA reference",
+			"ON ON-001", "[ON ON-001] is an invalid SOTA reference",
 		},
 		{
 			"Bad ref (numerical region)",
 			args{inputStr: "on/9N-001"},
-			"*ON/9N-001", "[ON/9N-001] is an invalid SOTA reference",
+			"ON/9N-001", "[ON/9N-001] is an invalid SOTA reference",
 		},
 		{
 			"Bad ref (too long region)",
 			args{inputStr: "on/ONA-001"},
-			"*ON/ONA-001", "[ON/ONA-001] is an invalid SOTA reference",
+			"ON/ONA-001", "[ON/ONA-001] is an invalid SOTA reference",
 		},
 		{
 			"Bad ref (no dash)",
 			args{inputStr: "on/ON/001"},
-			"*ON/ON/001", "[ON/ON/001] is an invalid SOTA reference",
+			"ON/ON/001", "[ON/ON/001] is an invalid SOTA reference",
 		},
 		{
 			"Bad ref (number too short)",
 			args{inputStr: "on/ON-01"},
-			"*ON/ON-01", "[ON/ON-01] is an invalid SOTA reference",
+			"ON/ON-01", "[ON/ON-01] is an invalid SOTA reference",
 		},
 		{
 			"Bad ref (Number too long)",
 			args{inputStr: "on/ON-9001"},
-			"*ON/ON-9001", "[ON/ON-9001] is an invalid SOTA reference",
+			"ON/ON-9001", "[ON/ON-9001] is an invalid SOTA reference",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRef, gotErrorMsg := ValidateSota(tt.args.inputStr)
+			gotRef, gotDiagnostic := ValidateSota(tt.args.inputStr)
 			if gotRef != tt.wantRef {
 				t.Errorf("ValidateSota() gotRef = %v, want %v", gotRef, tt.wantRef)
 			}
-			if gotErrorMsg != tt.wantErrorMsg {
+			if gotErrorMsg := diagnosticMessage(gotDiagnostic); gotErrorMsg != tt.wantErrorMsg {
 				t.Errorf("ValidateSota() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
 			}
 		})
@@ -206,46 +312,46 @@ func TestValidateCall(t *testing.T) {
 		{
 			"Pure junk passed",
 			args{sign: "aaaaaa"},
-			"*AAAAAA", "[AAAAAA] is an invalid call",
+			"AAAAAA", "[AAAAAA] is an invalid call",
 		},
 		{
 			"empty string",
 			args{sign: ""},
-			"*", "[] is an invalid call",
+			"", "[] is an invalid call",
 		},
 		{
 			"string with spaces",
 			args{sign: "  "},
-			"*", "[] is an invalid call",
+			"", "[] is an invalid call",
 		},
 		{
 			"invalid prefix",
 			args{sign: "xyz4/on4kjm"},
-			"*XYZ4/ON4KJM", "[XYZ4] is an invalid prefix",
+			"XYZ4/ON4KJM", "[XYZ4] is an invalid prefix",
 		},
 		{
 			"invalid prefix (when suffix is supplied)",
 			args{sign: "xyz4/on4kjm/p"},
-			"*XYZ4/ON4KJM/P", "[XYZ4] is an invalid prefix",
+			"XYZ4/ON4KJM/P", "[XYZ4] is an invalid prefix",
 		},
 		{
 			"Too many /",
 			args{sign: "F/on4kjm/p/x"},
-			"*F/ON4KJM/P/X", "[F/ON4KJM/P/X] is invalid: too many '/'",
+			"F/ON4KJM/P/X", "[F/ON4KJM/P/X] is invalid: too many '/'",
 		},
 		{
 			"signe /",
 			args{sign: "/"},
-			"*/", "[] is an invalid call",
+			"/", "[] is an invalid call",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotCall, gotErrorMsg := ValidateCall(tt.args.sign)
+			gotCall, gotDiagnostic := ValidateCall(tt.args.sign)
 			if gotCall != tt.wantCall {
 				t.Errorf("ValidateCall() gotCall = %v, want %v", gotCall, tt.wantCall)
 			}
-			if gotErrorMsg != tt.wantErrorMsg {
+			if gotErrorMsg := diagnosticMessage(gotDiagnostic); gotErrorMsg != tt.wantErrorMsg {
 				t.Errorf("ValidateCall() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
 			}
 		})
@@ -275,16 +381,16 @@ func TestValidateDate(t *testing.T) {
 		{
 			"Bad date (simple)",
 			args{inputStr: "2020-13-10"},
-			"*2020-13-10", "parsing time \"2020-13-10\": month out of range",
+			"2020-13-10", "parsing time \"2020-13-10\": month out of range",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRef, gotErrorMsg := ValidateDate(tt.args.inputStr)
+			gotRef, gotDiagnostic := ValidateDate(tt.args.inputStr)
 			if gotRef != tt.wantRef {
 				t.Errorf("ValidateDate() gotRef = %v, want %v", gotRef, tt.wantRef)
 			}
-			if gotErrorMsg != tt.wantErrorMsg {
+			if gotErrorMsg := diagnosticMessage(gotDiagnostic); gotErrorMsg != tt.wantErrorMsg {
 				t.Errorf("ValidateDate() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
 			}
 		})
@@ -318,6 +424,11 @@ func TestIsBand(t *testing.T) {
 			args{inputStr: "60M"},
 			true, 5.06, 5.45, "5MHz",
 		},
+		{
+			"microwave band",
+			args{inputStr: "23cm"},
+			true, 1240, 1300, "1.2GHz",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -351,7 +462,7 @@ func TestValidateGridLocator(t *testing.T) {
 		{
 			"invalid grid",
 			args{grid: "zzzz"},
-			"*zzzz", "[zzzz] is an invalid grid reference",
+			"zzzz", "[zzzz] is an invalid grid reference",
 		},
 		{
 			"Valid 4 pos grid",
@@ -376,16 +487,16 @@ func TestValidateGridLocator(t *testing.T) {
 		{
 			"Valid grid but over 6 pos",
 			args{grid: "JO20ec16"},
-			"*JO20ec16", "[JO20ec16] is an invalid grid reference",
+			"JO20ec16", "[JO20ec16] is an invalid grid reference",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotProcessedGrid, gotErrorMsg := ValidateGridLocator(tt.args.grid)
+			gotProcessedGrid, gotDiagnostic := ValidateGridLocator(tt.args.grid)
 			if gotProcessedGrid != tt.wantProcessedGrid {
 				t.Errorf("ValidateGridLocator() gotProcessedGrid = %v, want %v", gotProcessedGrid, tt.wantProcessedGrid)
 			}
-			if gotErrorMsg != tt.wantErrorMsg {
+			if gotErrorMsg := diagnosticMessage(gotDiagnostic); gotErrorMsg != tt.wantErrorMsg {
 				t.Errorf("ValidateGridLocator() gotErrorMsg = %v, want %v", gotErrorMsg, tt.wantErrorMsg)
 			}
 		})
@@ -435,22 +546,22 @@ func TestNormalizeDate(t *testing.T) {
 		{
 			"Bad date",
 			args{inputStr: "202009.04"},
-			"*202009.04", "Bad date format: found 2 elements while expecting 3.",
+			"202009.04", "Bad date format: found 2 elements while expecting 3.",
 		},
 		{
 			"Bad year length",
 			args{inputStr: "202009.09.15"},
-			"*202009.09.15", "Bad date format: first part doesn't look like a year",
+			"202009.09.15", "Bad date format: first part doesn't look like a year",
 		},
 		{
 			"Bad month length",
 			args{inputStr: "2020.091.15"},
-			"*2020.091.15", "Bad date format: second part doesn't look like a month",
+			"2020.091.15", "Bad date format: second part doesn't look like a month",
 		},
 		{
 			"Bad day length",
 			args{inputStr: "2020.09.015"},
-			"*2020.09.015", "Bad date format: third element doesn't look like a day",
+			"2020.09.015", "Bad date format: third element doesn't look like a day",
 		},
 	}
 	for _, tt := range tests {
@@ -485,17 +596,17 @@ func TestIncrementDate(t *testing.T) {
 		{
 			"increment below 0",
 			args{date: "2020-09-05", increment: 0},
-			"*2020-09-05", "Invalid day increment, expecting greater or equal to 1",
+			"2020-09-05", "Invalid day increment, expecting greater or equal to 1",
 		},
 		{
 			"increment above 10",
 			args{date: "2020-09-05", increment: 11},
-			"*2020-09-05", "Invalid day increment, expecting smaller or equal to 10",
+			"2020-09-05", "Invalid day increment, expecting smaller or equal to 10",
 		},
 		{
 			"Invalid date",
 			args{date: "2020-09-32", increment: 2},
-			"*2020-09-32", "(Internal error) error parsing time \"2020-09-32\": day out of range",
+			"2020-09-32", "(Internal error) error parsing time \"2020-09-32\": day out of range",
 		},
 		{
 			"happy case",
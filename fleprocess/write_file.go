@@ -2,7 +2,9 @@ package fleprocess
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -51,3 +53,16 @@ func checkFileError(e error) {
 		panic(e)
 	}
 }
+
+//lineCountingWriter counts the lines written to the underlying writer
+type lineCountingWriter struct {
+	writer    io.Writer
+	lineCount int
+}
+
+//Write writes the data to the underlying writer and counts the end of lines
+func (countingWriter *lineCountingWriter) Write(data []byte) (int, error) {
+	written, err := countingWriter.writer.Write(data)
+	countingWriter.lineCount += bytes.Count(data[:written], []byte("\n"))
+	return written, err
+}
@@ -32,6 +32,34 @@ echo "\`\`\`"  >> help.txt
 echo " " >> help.txt
 echo " " >> help.txt
 
+echo "## \"CABRILLO\" command"  >> help.txt
+echo "\`\`\`"  >> help.txt
+../dist/FLEcli_darwin_amd64/FLEcli cabrillo --help >> help.txt
+echo "\`\`\`"  >> help.txt
+echo " " >> help.txt
+echo " " >> help.txt
+
+echo "## \"FMT\" command"  >> help.txt
+echo "\`\`\`"  >> help.txt
+../dist/FLEcli_darwin_amd64/FLEcli fmt --help >> help.txt
+echo "\`\`\`"  >> help.txt
+echo " " >> help.txt
+echo " " >> help.txt
+
+echo "## \"FROMADIF\" command"  >> help.txt
+echo "\`\`\`"  >> help.txt
+../dist/FLEcli_darwin_amd64/FLEcli fromadif --help >> help.txt
+echo "\`\`\`"  >> help.txt
+echo " " >> help.txt
+echo " " >> help.txt
+
+echo "## \"VALIDATE-ADIF\" command"  >> help.txt
+echo "\`\`\`"  >> help.txt
+../dist/FLEcli_darwin_amd64/FLEcli validate-adif --help >> help.txt
+echo "\`\`\`"  >> help.txt
+echo " " >> help.txt
+echo " " >> help.txt
+
 echo "## \"VERSION\" command"  >> help.txt
 echo "\`\`\`"  >> help.txt
 ../dist/FLEcli_darwin_amd64/FLEcli version --help >> help.txt